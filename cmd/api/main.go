@@ -2,13 +2,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	_ "time/tzdata" // provider time zones must resolve even without system zoneinfo
 
 	"server/internal/config"
 	"server/internal/database"
 	"server/internal/handlers"
 	"server/internal/repositories"
 	"server/internal/services"
+	"server/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-migrate/migrate/v4"
@@ -41,20 +44,84 @@ func main() {
 	// Run database migrations
 	runMigrations(db)
 
+	// Initialize media storage
+	mediaStore, err := storage.NewLocal(cfg.MediaDir)
+	if err != nil {
+		log.Fatalf("Media storage setup failed: %v", err)
+	}
+
 	// Initialize repositories
+	transactor := repositories.NewTransactor(db)
 	categoryRepo := repositories.NewCategoryRepo(db)
 	serviceRepo := repositories.NewServiceRepo(db)
+	searchRepo := repositories.NewSearchRepo(db)
+	providerRepo := repositories.NewProviderRepo(db)
+	bookingRepo := repositories.NewBookingRepo(db)
+	availabilityRepo := repositories.NewAvailabilityRepo(db)
+	areaRepo := repositories.NewAreaRepo(db)
+	reviewRepo := repositories.NewReviewRepo(db)
+	mediaRepo := repositories.NewMediaRepo(db)
+	translationRepo := repositories.NewTranslationRepo(db)
+	trashRepo := repositories.NewTrashRepo(db)
+	auditRepo := repositories.NewAuditRepo(db)
+	exportRepo := repositories.NewExportRepo(db)
+	slugRepo := repositories.NewSlugRepo(db)
+	tagRepo := repositories.NewTagRepo(db)
+	optionRepo := repositories.NewOptionRepo(db)
+	bundleRepo := repositories.NewBundleRepo(db)
+	scheduleRepo := repositories.NewScheduleRepo(db)
+	revisionRepo := repositories.NewRevisionRepo(db)
 
 	// Initialize services
-	categoryService := services.NewCategoryService(categoryRepo)
-	serviceService := services.NewServiceService(serviceRepo, categoryRepo)
+	categoryService := services.NewCategoryService(transactor, categoryRepo, mediaRepo, translationRepo, auditRepo, slugRepo, revisionRepo, serviceRepo, bundleRepo)
+	serviceService := services.NewServiceService(transactor, serviceRepo, categoryRepo, mediaRepo, translationRepo, auditRepo, slugRepo, optionRepo, bundleRepo, revisionRepo)
+	searchService := services.NewSearchService(searchRepo)
+	providerService := services.NewProviderService(providerRepo, serviceRepo)
+	bookingService := services.NewBookingService(transactor, bookingRepo, serviceRepo, categoryRepo, providerRepo)
+	availabilityService := services.NewAvailabilityService(availabilityRepo, providerRepo, serviceRepo)
+	areaService := services.NewAreaService(areaRepo, serviceRepo, providerRepo)
+	reviewService := services.NewReviewService(transactor, reviewRepo, serviceRepo)
+	mediaService := services.NewMediaService(transactor, mediaRepo, serviceRepo, categoryRepo, mediaStore, cfg.MaxUploadBytes)
+	translationService := services.NewTranslationService(transactor, translationRepo, categoryRepo, serviceRepo)
+	trashService := services.NewTrashService(transactor, trashRepo, mediaStore, cfg.TrashRetention)
+	auditService := services.NewAuditService(auditRepo)
+	importService := services.NewImportService(transactor, categoryRepo, serviceRepo, categoryService, serviceService)
+	exportService := services.NewExportService(exportRepo)
+	tagService := services.NewTagService(tagRepo, serviceRepo)
+	optionService := services.NewOptionService(transactor, optionRepo, serviceRepo)
+	bundleService := services.NewBundleService(transactor, bundleRepo, serviceRepo, categoryRepo)
+	scheduleService := services.NewScheduleService(transactor, scheduleRepo, categoryRepo, serviceRepo, bundleRepo, auditRepo)
 
 	// Initialize handlers
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	serviceHandler := handlers.NewServiceHandler(serviceService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	providerHandler := handlers.NewProviderHandler(providerService)
+	bookingHandler := handlers.NewBookingHandler(bookingService)
+	availabilityHandler := handlers.NewAvailabilityHandler(availabilityService)
+	areaHandler := handlers.NewAreaHandler(areaService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	translationHandler := handlers.NewTranslationHandler(translationService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	optionHandler := handlers.NewOptionHandler(optionService)
+	bundleHandler := handlers.NewBundleHandler(bundleService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+
+	// Purge expired trash in the background
+	go trashService.RunPurge(context.Background(), cfg.TrashPurgeInterval)
+
+	// Apply due publish and unpublish times in the background
+	go scheduleService.RunScheduler(context.Background(), cfg.SchedulerInterval)
 
 	// Create router
 	r := gin.Default()
+	r.Use(handlers.RequestContext())
+	r.Use(handlers.AdminAuth(cfg.AdminToken))
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -75,21 +142,146 @@ func main() {
 	// Category routes
 	r.POST("/categories", categoryHandler.CreateCategory)
 	r.GET("/categories", categoryHandler.ListCategories)
+	r.PUT("/categories/reorder", categoryHandler.ReorderCategories)
 	
 	// IMPORTANT: The more specific route comes first
 	r.GET("/categories/:id/services", serviceHandler.ListServicesByCategory)
+	r.PUT("/categories/:id/services/reorder", serviceHandler.ReorderServices)
+	r.GET("/categories/:id/bundles", bundleHandler.ListBundlesByCategory)
+	r.GET("/categories/:id/ancestors", categoryHandler.GetCategoryAncestors)
+	r.GET("/categories/:id/children", categoryHandler.ListChildCategories)
+	r.PUT("/categories/:id/children/reorder", categoryHandler.ReorderChildCategories)
+	r.GET("/categories/:id/tree", categoryHandler.GetCategoryTree)
+	r.GET("/categories/:id/media", mediaHandler.ListCategoryMedia)
+	r.POST("/categories/:id/media", mediaHandler.UploadCategoryMedia)
+	r.GET("/categories/:id/translations", translationHandler.ListCategoryTranslations)
+	r.PUT("/categories/:id/translations/:locale", translationHandler.SetCategoryTranslation)
+	r.DELETE("/categories/:id/translations/:locale", translationHandler.DeleteCategoryTranslation)
 	
 	// General category routes
 	r.GET("/categories/:id", categoryHandler.GetCategory)
 	r.PUT("/categories/:id", categoryHandler.UpdateCategory)
+	r.PATCH("/categories/:id", categoryHandler.PatchCategory)
 	r.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+	r.POST("/categories/:id/restore", categoryHandler.RestoreCategory)
+	r.POST("/categories/:id/publish", handlers.RequireAdmin(), categoryHandler.PublishCategory)
+	r.GET("/categories/:id/revisions", handlers.RequireAdmin(), categoryHandler.ListCategoryRevisions)
+	r.GET("/categories/:id/revisions/diff", handlers.RequireAdmin(), categoryHandler.DiffCategoryRevisions)
+	r.POST("/categories/:id/revisions/:number/rollback", handlers.RequireAdmin(), categoryHandler.RollbackCategory)
 
 	// Service routes
 	r.POST("/services", serviceHandler.CreateService)
 	r.GET("/services", serviceHandler.ListServices)
 	r.GET("/services/:id", serviceHandler.GetService)
+	r.GET("/services/:id/prices", serviceHandler.ListServicePrices)
+	r.GET("/services/:id/providers", providerHandler.ListServiceProviders)
+	r.GET("/services/:id/availability", availabilityHandler.GetServiceAvailability)
+	r.GET("/services/:id/areas", areaHandler.ListServiceAreas)
+	r.POST("/services/:id/areas", areaHandler.CreateServiceArea)
+	r.GET("/services/:id/reviews", reviewHandler.ListServiceReviews)
+	r.POST("/services/:id/reviews", reviewHandler.CreateReview)
+	r.GET("/services/:id/media", mediaHandler.ListServiceMedia)
+	r.POST("/services/:id/media", mediaHandler.UploadServiceMedia)
+	r.GET("/services/:id/translations", translationHandler.ListServiceTranslations)
+	r.PUT("/services/:id/translations/:locale", translationHandler.SetServiceTranslation)
+	r.DELETE("/services/:id/translations/:locale", translationHandler.DeleteServiceTranslation)
+	r.GET("/services/:id/tags", tagHandler.ListServiceTags)
+	r.PUT("/services/:id/tags/:tagId", tagHandler.AttachTag)
+	r.DELETE("/services/:id/tags/:tagId", tagHandler.DetachTag)
+	r.GET("/services/:id/option-groups", optionHandler.ListOptionGroups)
+	r.POST("/services/:id/option-groups", optionHandler.CreateOptionGroup)
+	r.POST("/services/:id/quote", optionHandler.QuoteService)
 	r.PUT("/services/:id", serviceHandler.UpdateService)
+	r.PATCH("/services/:id", serviceHandler.PatchService)
 	r.DELETE("/services/:id", serviceHandler.DeleteService)
+	r.POST("/services/:id/restore", serviceHandler.RestoreService)
+	r.POST("/services/:id/publish", handlers.RequireAdmin(), serviceHandler.PublishService)
+	r.GET("/services/:id/revisions", handlers.RequireAdmin(), serviceHandler.ListServiceRevisions)
+	r.GET("/services/:id/revisions/diff", handlers.RequireAdmin(), serviceHandler.DiffServiceRevisions)
+	r.POST("/services/:id/revisions/:number/rollback", handlers.RequireAdmin(), serviceHandler.RollbackService)
+
+	// Provider routes
+	r.POST("/providers", providerHandler.CreateProvider)
+	r.GET("/providers", providerHandler.ListProviders)
+	r.GET("/providers/:id", providerHandler.GetProvider)
+	r.PUT("/providers/:id", providerHandler.UpdateProvider)
+	r.DELETE("/providers/:id", providerHandler.DeleteProvider)
+	r.GET("/providers/:id/services", providerHandler.ListProviderServices)
+	r.PUT("/providers/:id/services/:serviceId", providerHandler.AttachProviderService)
+	r.DELETE("/providers/:id/services/:serviceId", providerHandler.DetachProviderService)
+	r.GET("/providers/:id/working-hours", availabilityHandler.GetWorkingHours)
+	r.PUT("/providers/:id/working-hours", availabilityHandler.SetWorkingHours)
+	r.GET("/providers/:id/exceptions", availabilityHandler.ListExceptions)
+	r.PUT("/providers/:id/exceptions/:date", availabilityHandler.SetException)
+	r.DELETE("/providers/:id/exceptions/:date", availabilityHandler.DeleteException)
+	r.GET("/providers/:id/blackouts", availabilityHandler.ListBlackouts)
+	r.POST("/providers/:id/blackouts", availabilityHandler.CreateBlackout)
+	r.DELETE("/providers/:id/blackouts/:blackoutId", availabilityHandler.DeleteBlackout)
+	r.GET("/providers/:id/areas", areaHandler.ListProviderAreas)
+	r.POST("/providers/:id/areas", areaHandler.CreateProviderArea)
+
+	// Service area routes
+	r.DELETE("/areas/:id", areaHandler.DeleteArea)
+
+	// Booking routes
+	r.POST("/bookings", bookingHandler.CreateBooking)
+	r.GET("/bookings", bookingHandler.ListBookings)
+	r.GET("/bookings/:id", bookingHandler.GetBooking)
+	r.POST("/bookings/:id/transitions", bookingHandler.TransitionBooking)
+	r.GET("/bookings/:id/events", bookingHandler.ListBookingEvents)
+
+	// Review routes
+	r.GET("/reviews", reviewHandler.ListReviews)
+	r.PUT("/reviews/:id", reviewHandler.EditReview)
+	r.PUT("/reviews/:id/moderation", handlers.RequireAdmin(), reviewHandler.ModerateReview)
+	r.POST("/reviews/:id/helpful", reviewHandler.MarkReviewHelpful)
+
+	// Media routes
+	r.PUT("/media/:id", mediaHandler.UpdateMedia)
+	r.DELETE("/media/:id", mediaHandler.DeleteMedia)
+	r.GET("/media/:id/file", mediaHandler.GetMediaFile)
+	r.GET("/media/:id/thumbnail", mediaHandler.GetMediaThumbnail)
+
+	// Tag routes
+	r.POST("/tags", tagHandler.CreateTag)
+	r.GET("/tags", tagHandler.ListTags)
+	r.PUT("/tags/:id", tagHandler.UpdateTag)
+	r.DELETE("/tags/:id", tagHandler.DeleteTag)
+
+	// Bundle routes
+	r.POST("/bundles", bundleHandler.CreateBundle)
+	r.GET("/bundles", bundleHandler.ListBundles)
+	r.GET("/bundles/:id", bundleHandler.GetBundle)
+	r.PUT("/bundles/:id", bundleHandler.UpdateBundle)
+	r.DELETE("/bundles/:id", bundleHandler.DeleteBundle)
+
+	// Option routes
+	r.PUT("/option-groups/:id", optionHandler.UpdateOptionGroup)
+	r.DELETE("/option-groups/:id", optionHandler.DeleteOptionGroup)
+	r.POST("/option-groups/:id/options", optionHandler.CreateOption)
+	r.PUT("/options/:id", optionHandler.UpdateOption)
+	r.DELETE("/options/:id", optionHandler.DeleteOption)
+
+	// Translation routes
+	r.GET("/translations/missing", translationHandler.MissingTranslations)
+
+	// Trash routes
+	r.GET("/trash", trashHandler.ListTrash)
+
+	// Schedule routes
+	r.GET("/schedule", scheduleHandler.ListScheduled)
+
+	// Audit routes
+	r.GET("/audit", auditHandler.ListAudit)
+
+	// Import routes
+	r.POST("/import", importHandler.ImportCatalog)
+
+	// Export routes
+	r.GET("/export", exportHandler.ExportCatalog)
+
+	// Search routes
+	r.GET("/search", searchHandler.Search)
 
 	// Start server
 	log.Printf("Server running on port %s", cfg.Port)
@@ -0,0 +1,108 @@
+// Package audit carries who is making a request through the context and
+// computes the field-by-field difference between two versions of a record.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Anonymous is the actor recorded for requests that do not identify one.
+const Anonymous = "anonymous"
+
+// Admin is the actor recorded for requests made with the admin token.
+const Admin = "admin"
+
+type requestKey struct{}
+
+type request struct {
+	actor string
+	id    string
+	admin bool
+}
+
+// WithRequest returns a context carrying the actor and ID of the request it
+// belongs to.
+func WithRequest(ctx context.Context, actor, requestID string) context.Context {
+	return context.WithValue(ctx, requestKey{}, request{actor: actor, id: requestID})
+}
+
+// WithAdmin returns a context whose request was made with the admin token.
+// Its actor is Admin whatever the request claimed to be.
+func WithAdmin(ctx context.Context) context.Context {
+	r, _ := ctx.Value(requestKey{}).(request)
+	r.actor, r.admin = Admin, true
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// Actor returns the actor carried by ctx, or Anonymous.
+func Actor(ctx context.Context) string {
+	if r, ok := ctx.Value(requestKey{}).(request); ok && r.actor != "" {
+		return r.actor
+	}
+	return Anonymous
+}
+
+// IsAdmin reports whether the request carried by ctx was made with the admin
+// token. When it was not, its actor is only what the client claimed.
+func IsAdmin(ctx context.Context) bool {
+	r, _ := ctx.Value(requestKey{}).(request)
+	return r.admin
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) string {
+	r, _ := ctx.Value(requestKey{}).(request)
+	return r.id
+}
+
+// Change is the old and new value of one field.
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Diff compares the JSON forms of two versions of a record and returns the
+// fields whose values differ. Either version may be nil, for records being
+// created or deleted.
+func Diff(before, after any) (map[string]Change, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for name, old := range b {
+		if v, ok := a[name]; !ok || !reflect.DeepEqual(old, v) {
+			changes[name] = Change{From: old, To: a[name]}
+		}
+	}
+	for name, v := range a {
+		if _, ok := b[name]; !ok {
+			changes[name] = Change{From: nil, To: v}
+		}
+	}
+	return changes, nil
+}
+
+// fields decodes the JSON object form of v into its top-level fields.
+func fields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode record: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("record is not an object: %w", err)
+	}
+	return m, nil
+}
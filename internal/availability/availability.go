@@ -0,0 +1,184 @@
+// Package availability computes bookable time slots from a provider's weekly
+// working hours, date exceptions, blackout dates and existing bookings.
+//
+// Working hours are wall-clock times in the provider's IANA time zone, so a
+// 09:00–17:00 day stays 09:00–17:00 local time on both sides of a DST change
+// while the absolute slot times shift accordingly.
+package availability
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DateLayout is the format of calendar dates used by exceptions and
+// blackouts.
+const DateLayout = "2006-01-02"
+
+// MinutesPerDay is the largest valid Clock value, meaning the end of the day.
+const MinutesPerDay = 24 * 60
+
+// Clock is a wall-clock time of day in minutes after local midnight.
+type Clock int
+
+// Window is a range of wall-clock time within one day, [Start, End).
+type Window struct {
+	Start Clock
+	End   Clock
+}
+
+// DateRange is an inclusive range of calendar dates in DateLayout.
+type DateRange struct {
+	From string
+	To   string
+}
+
+// Slot is an absolute time range, [Start, End).
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Schedule describes when a provider works.
+type Schedule struct {
+	// Location is the provider's time zone. Nil means UTC.
+	Location *time.Location
+	// Weekly holds the regular working windows per weekday.
+	Weekly map[time.Weekday][]Window
+	// Exceptions replace the weekly windows on specific dates (DateLayout).
+	// A date mapped to no windows is a day off.
+	Exceptions map[string][]Window
+	// Blackouts are date ranges with no availability at all.
+	Blackouts []DateRange
+}
+
+// ValidateWindows checks that windows lie within a day, are non-empty and do
+// not overlap each other.
+func ValidateWindows(windows []Window) error {
+	sorted := append([]Window(nil), windows...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	for i, w := range sorted {
+		if w.Start < 0 || w.End > MinutesPerDay {
+			return fmt.Errorf("window %s must lie within the day", w)
+		}
+		if w.End <= w.Start {
+			return fmt.Errorf("window %s must end after it starts", w)
+		}
+		if i > 0 && w.Start < sorted[i-1].End {
+			return fmt.Errorf("windows %s and %s overlap", sorted[i-1], w)
+		}
+	}
+	return nil
+}
+
+// ValidateDateRange checks both dates parse and are in order.
+func ValidateDateRange(r DateRange) error {
+	from, err := time.Parse(DateLayout, r.From)
+	if err != nil {
+		return fmt.Errorf("invalid start date %q", r.From)
+	}
+	to, err := time.Parse(DateLayout, r.To)
+	if err != nil {
+		return fmt.Errorf("invalid end date %q", r.To)
+	}
+	if to.Before(from) {
+		return errors.New("end date cannot be before start date")
+	}
+	return nil
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// Slots returns every slot of the given duration that starts on a step
+// boundary within a working window, lies inside [from, to) and does not
+// overlap any busy range. Slots are returned in chronological order.
+func (s Schedule) Slots(from, to time.Time, duration, step time.Duration, busy []Slot) []Slot {
+	if duration <= 0 || !to.After(from) {
+		return nil
+	}
+	if step <= 0 {
+		step = duration
+	}
+
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	busy = append([]Slot(nil), busy...)
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+
+	var slots []Slot
+	fy, fm, fd := from.In(loc).Date()
+	last := to.In(loc)
+	for day := time.Date(fy, fm, fd, 0, 0, 0, 0, loc); day.Before(last); {
+		y, m, d := day.Date()
+		for _, w := range s.windowsOn(y, m, d, day.Weekday()) {
+			start := wallClock(y, m, d, w.Start, loc)
+			end := wallClock(y, m, d, w.End, loc)
+			for t := start; !t.Add(duration).After(end); t = t.Add(step) {
+				slot := Slot{Start: t, End: t.Add(duration)}
+				if slot.Start.Before(from) || slot.End.After(to) || overlapsAny(slot, busy) {
+					continue
+				}
+				slots = append(slots, slot)
+			}
+		}
+		// Advance by calendar day rather than 24h so days that are 23 or
+		// 25 hours long around DST changes are not skipped or repeated.
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return slots
+}
+
+// windowsOn returns the working windows of a calendar date, sorted by start.
+func (s Schedule) windowsOn(y int, m time.Month, d int, weekday time.Weekday) []Window {
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(DateLayout)
+	for _, b := range s.Blackouts {
+		// DateLayout sorts lexically in calendar order.
+		if b.From <= date && date <= b.To {
+			return nil
+		}
+	}
+
+	windows, ok := s.Exceptions[date]
+	if !ok {
+		windows = s.Weekly[weekday]
+	}
+	windows = append([]Window(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start < windows[j].Start })
+	return windows
+}
+
+// wallClock returns the instant a local clock shows c on the given date.
+// A time skipped by a spring-forward change resolves to the same distance
+// past the change (02:30 becomes 03:30), and a time repeated by a fall-back
+// change resolves to its first occurrence.
+func wallClock(y int, m time.Month, d int, c Clock, loc *time.Location) time.Time {
+	t := time.Date(y, m, d, int(c)/60, int(c)%60, 0, 0, loc)
+
+	// time.Date normalizes skipped times using the offset in force after the
+	// change, which lands before the gap; step forward by the difference.
+	h, mi, _ := t.Clock()
+	if got := Clock(h*60 + mi); got != c%MinutesPerDay {
+		t = t.Add(time.Duration(c%MinutesPerDay-got) * time.Minute)
+	}
+	return t
+}
+
+func overlapsAny(slot Slot, busy []Slot) bool {
+	for _, b := range busy {
+		if !b.Start.Before(slot.End) {
+			break
+		}
+		if b.End.After(slot.Start) {
+			return true
+		}
+	}
+	return false
+}
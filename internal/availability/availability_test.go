@@ -0,0 +1,340 @@
+package availability
+
+import (
+	"slices"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func utc(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func hours(from, to int) Window {
+	return Window{Start: Clock(from * 60), End: Clock(to * 60)}
+}
+
+func TestSlots(t *testing.T) {
+	newYork := mustLoad(t, "America/New_York")
+	kolkata := mustLoad(t, "Asia/Kolkata")
+
+	// Each day of the week, so a test only has to pick its dates.
+	everyDay := func(windows ...Window) map[time.Weekday][]Window {
+		weekly := make(map[time.Weekday][]Window, 7)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			weekly[d] = windows
+		}
+		return weekly
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from, to string
+		duration time.Duration
+		step     time.Duration
+		busy     []Slot
+		want     []string
+	}{
+		{
+			name:     "UTC when no location is given",
+			schedule: Schedule{Weekly: everyDay(hours(9, 12))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-03T10:00:00Z", "2024-06-03T11:00:00Z"},
+		},
+		{
+			name:     "half-hour offset zone",
+			schedule: Schedule{Location: kolkata, Weekly: everyDay(hours(9, 11))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-03T12:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T03:30:00Z", "2024-06-03T04:30:00Z"},
+		},
+		{
+			name:     "local day spans two UTC days",
+			schedule: Schedule{Location: newYork, Weekly: map[time.Weekday][]Window{time.Monday: {hours(19, 21)}}},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-05T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T23:00:00Z", "2024-06-04T00:00:00Z"},
+		},
+		{
+			name:     "only the weekdays with hours",
+			schedule: Schedule{Weekly: map[time.Weekday][]Window{time.Tuesday: {hours(9, 10)}}},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-10T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-04T09:00:00Z"},
+		},
+		{
+			name:     "same wall-clock hours either side of spring forward",
+			schedule: Schedule{Location: newYork, Weekly: everyDay(hours(9, 10))},
+			from:     "2024-03-09T00:00:00Z",
+			to:       "2024-03-12T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-03-09T14:00:00Z", "2024-03-10T13:00:00Z", "2024-03-11T13:00:00Z"},
+		},
+		{
+			name:     "window across the skipped hour",
+			schedule: Schedule{Location: newYork, Weekly: everyDay(hours(1, 4))},
+			from:     "2024-03-10T00:00:00Z",
+			to:       "2024-03-10T12:00:00Z",
+			duration: time.Hour,
+			// 01:00 EST to 04:00 EDT is only two hours long.
+			want: []string{"2024-03-10T06:00:00Z", "2024-03-10T07:00:00Z"},
+		},
+		{
+			name: "window starting in the skipped hour",
+			schedule: Schedule{Location: newYork, Weekly: everyDay(
+				Window{Start: 2*60 + 30, End: 4 * 60})},
+			from:     "2024-03-10T00:00:00Z",
+			to:       "2024-03-10T12:00:00Z",
+			duration: 30 * time.Minute,
+			// 02:30 does not exist and resolves to 03:30 EDT.
+			want: []string{"2024-03-10T07:30:00Z"},
+		},
+		{
+			name:     "window across the repeated hour",
+			schedule: Schedule{Location: newYork, Weekly: everyDay(hours(0, 3))},
+			from:     "2024-11-03T00:00:00Z",
+			to:       "2024-11-03T12:00:00Z",
+			duration: time.Hour,
+			// 00:00 EDT to 03:00 EST is four hours long.
+			want: []string{
+				"2024-11-03T04:00:00Z", "2024-11-03T05:00:00Z",
+				"2024-11-03T06:00:00Z", "2024-11-03T07:00:00Z",
+			},
+		},
+		{
+			name:     "same wall-clock hours either side of fall back",
+			schedule: Schedule{Location: newYork, Weekly: everyDay(hours(9, 10))},
+			from:     "2024-11-02T00:00:00Z",
+			to:       "2024-11-05T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-11-02T13:00:00Z", "2024-11-03T14:00:00Z", "2024-11-04T14:00:00Z"},
+		},
+		{
+			name:     "window running to midnight",
+			schedule: Schedule{Weekly: everyDay(Window{Start: 22 * 60, End: MinutesPerDay})},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T22:00:00Z", "2024-06-03T23:00:00Z"},
+		},
+		{
+			name: "exception replaces the weekly hours",
+			schedule: Schedule{
+				Weekly:     everyDay(hours(9, 17)),
+				Exceptions: map[string][]Window{"2024-06-03": {hours(13, 14), hours(8, 9)}},
+			},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T08:00:00Z", "2024-06-03T13:00:00Z"},
+		},
+		{
+			name: "exception with no windows is a day off",
+			schedule: Schedule{
+				Weekly:     everyDay(hours(9, 10)),
+				Exceptions: map[string][]Window{"2024-06-04": nil},
+			},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-06T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-05T09:00:00Z"},
+		},
+		{
+			name: "exception on a day without weekly hours",
+			schedule: Schedule{
+				Weekly:     map[time.Weekday][]Window{},
+				Exceptions: map[string][]Window{"2024-06-08": {hours(10, 11)}},
+			},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-10T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-08T10:00:00Z"},
+		},
+		{
+			name: "exception dates are local dates",
+			schedule: Schedule{
+				Location:   newYork,
+				Weekly:     everyDay(hours(21, 22)),
+				Exceptions: map[string][]Window{"2024-06-04": nil},
+			},
+			from:     "2024-06-04T00:00:00Z",
+			to:       "2024-06-06T02:00:00Z",
+			duration: time.Hour,
+			// 21:00 EDT on 3 June is 01:00Z on 4 June; 4 June local is off.
+			want: []string{"2024-06-04T01:00:00Z", "2024-06-06T01:00:00Z"},
+		},
+		{
+			name: "blackout covers every day in range",
+			schedule: Schedule{
+				Weekly:     everyDay(hours(9, 10)),
+				Exceptions: map[string][]Window{"2024-06-05": {hours(9, 10)}},
+				Blackouts:  []DateRange{{From: "2024-06-04", To: "2024-06-05"}},
+			},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-07T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-06T09:00:00Z"},
+		},
+		{
+			name: "single-day blackout",
+			schedule: Schedule{
+				Weekly:    everyDay(hours(9, 10)),
+				Blackouts: []DateRange{{From: "2024-06-04", To: "2024-06-04"}},
+			},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-06T00:00:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-05T09:00:00Z"},
+		},
+		{
+			name:     "busy booking splits a window",
+			schedule: Schedule{Weekly: everyDay(hours(9, 12))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			step:     30 * time.Minute,
+			busy:     []Slot{{Start: utc("2024-06-03T10:00:00Z"), End: utc("2024-06-03T10:30:00Z")}},
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-03T10:30:00Z", "2024-06-03T11:00:00Z"},
+		},
+		{
+			name:     "unsorted busy bookings",
+			schedule: Schedule{Weekly: everyDay(hours(9, 13))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			busy: []Slot{
+				{Start: utc("2024-06-03T12:00:00Z"), End: utc("2024-06-03T13:00:00Z")},
+				{Start: utc("2024-06-03T09:30:00Z"), End: utc("2024-06-03T10:15:00Z")},
+			},
+			want: []string{"2024-06-03T11:00:00Z"},
+		},
+		{
+			name:     "busy booking covering the whole window",
+			schedule: Schedule{Weekly: everyDay(hours(9, 12))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			busy:     []Slot{{Start: utc("2024-06-03T08:00:00Z"), End: utc("2024-06-03T13:00:00Z")}},
+			want:     nil,
+		},
+		{
+			name:     "duration longer than the window",
+			schedule: Schedule{Weekly: everyDay(hours(9, 10), hours(14, 17))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: 90 * time.Minute,
+			want:     []string{"2024-06-03T14:00:00Z", "2024-06-03T15:30:00Z"},
+		},
+		{
+			name:     "duration longer than every window",
+			schedule: Schedule{Weekly: everyDay(hours(9, 10))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-10T00:00:00Z",
+			duration: 2 * time.Hour,
+			want:     nil,
+		},
+		{
+			name:     "slots clipped to the requested range",
+			schedule: Schedule{Weekly: everyDay(hours(9, 13))},
+			from:     "2024-06-03T10:00:00Z",
+			to:       "2024-06-03T12:30:00Z",
+			duration: time.Hour,
+			want:     []string{"2024-06-03T10:00:00Z", "2024-06-03T11:00:00Z"},
+		},
+		{
+			name:     "step shorter than the duration",
+			schedule: Schedule{Weekly: everyDay(hours(9, 11))},
+			from:     "2024-06-03T00:00:00Z",
+			to:       "2024-06-04T00:00:00Z",
+			duration: time.Hour,
+			step:     30 * time.Minute,
+			want:     []string{"2024-06-03T09:00:00Z", "2024-06-03T09:30:00Z", "2024-06-03T10:00:00Z"},
+		},
+		{
+			name:     "empty range",
+			schedule: Schedule{Weekly: everyDay(hours(9, 17))},
+			from:     "2024-06-03T12:00:00Z",
+			to:       "2024-06-03T12:00:00Z",
+			duration: time.Hour,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := tt.schedule.Slots(utc(tt.from), utc(tt.to), tt.duration, tt.step, tt.busy)
+			var got []string
+			for _, s := range slots {
+				if s.End.Sub(s.Start) != tt.duration {
+					t.Errorf("slot at %s lasts %s, want %s", s.Start.UTC().Format(time.RFC3339), s.End.Sub(s.Start), tt.duration)
+				}
+				got = append(got, s.Start.UTC().Format(time.RFC3339))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("slots starting\n\t%v\nwant\n\t%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []Window
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"whole day", []Window{{Start: 0, End: MinutesPerDay}}, false},
+		{"adjacent", []Window{hours(13, 17), hours(9, 13)}, false},
+		{"overlapping", []Window{hours(12, 17), hours(9, 13)}, true},
+		{"empty", []Window{hours(9, 9)}, true},
+		{"backwards", []Window{hours(17, 9)}, true},
+		{"past midnight", []Window{{Start: 23 * 60, End: MinutesPerDay + 1}}, true},
+		{"negative", []Window{{Start: -1, End: 60}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateWindows(tt.windows); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWindows(%v) = %v, want error %t", tt.windows, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	tests := []struct {
+		r       DateRange
+		wantErr bool
+	}{
+		{DateRange{From: "2024-06-03", To: "2024-06-03"}, false},
+		{DateRange{From: "2024-06-03", To: "2024-07-01"}, false},
+		{DateRange{From: "2024-06-03", To: "2024-06-02"}, true},
+		{DateRange{From: "2024-02-30", To: "2024-03-01"}, true},
+		{DateRange{From: "2024-06-03", To: "06/04/2024"}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateDateRange(tt.r); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateDateRange(%+v) = %v, want error %t", tt.r, err, tt.wantErr)
+		}
+	}
+}
@@ -3,13 +3,33 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 )
 
 type Config struct {
         DatabaseURL string
         Port        string
+
+        // MediaDir is where uploaded media files are stored.
+        MediaDir string
+        // MaxUploadBytes bounds the size of a single media upload.
+        MaxUploadBytes int64
+
+        // TrashRetention is how long deleted items stay restorable before
+        // the purge removes them; TrashPurgeInterval is how often it runs.
+        TrashRetention     time.Duration
+        TrashPurgeInterval time.Duration
+
+        // SchedulerInterval is how often due publish and unpublish times
+        // are applied.
+        SchedulerInterval time.Duration
+
+        // AdminToken is the bearer token admins authenticate with. Admin-only
+        // features are unavailable while it is empty.
+        AdminToken string
 }
 
 func LoadConfig() (*Config, error) { // Return *Config and error
@@ -28,8 +48,56 @@ func LoadConfig() (*Config, error) { // Return *Config and error
 			port = "8080" // Default if PORT is not set
 	}
 
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	maxUpload := int64(10 << 20)
+	if v := os.Getenv("MAX_UPLOAD_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid MAX_UPLOAD_BYTES %q", v)
+		}
+		maxUpload = n
+	}
+
+	retention, err := durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	purgeInterval, err := durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	schedulerInterval, err := durationEnv("SCHEDULER_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	adminToken := os.Getenv("ADMIN_TOKEN")
+
 	return &Config{
-			DatabaseURL: dbURL,
-			Port:        port,
+			DatabaseURL:        dbURL,
+			Port:               port,
+			MediaDir:           mediaDir,
+			MaxUploadBytes:     maxUpload,
+			TrashRetention:     retention,
+			TrashPurgeInterval: purgeInterval,
+			SchedulerInterval:  schedulerInterval,
+			AdminToken:         adminToken,
 	}, nil // Return nil error if all is well
-}
\ No newline at end of file
+}
+
+// durationEnv reads a positive time.Duration such as "720h" from the
+// environment, returning def when the variable is unset.
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return d, nil
+}
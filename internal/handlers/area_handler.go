@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AreaHandler struct {
+	service *services.AreaService
+}
+
+func NewAreaHandler(service *services.AreaService) *AreaHandler {
+	return &AreaHandler{service: service}
+}
+
+// CreateServiceArea godoc
+// @Summary Add an area where a service is offered
+// @Description kind is postal_codes (with postal_codes) or radius (with center_lat, center_lng, radius_km).
+// @Tags Areas
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param area body models.ServiceArea true "Area data"
+// @Success 201 {object} models.ServiceArea
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/areas [post]
+func (h *AreaHandler) CreateServiceArea(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.ServiceArea
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	area, err := h.service.CreateServiceArea(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, area)
+}
+
+// ListServiceAreas godoc
+// @Summary List the areas attached directly to a service
+// @Tags Areas
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.ServiceArea
+// @Router /services/{id}/areas [get]
+func (h *AreaHandler) ListServiceAreas(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	areas, err := h.service.ListServiceAreas(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, areas)
+}
+
+// CreateProviderArea godoc
+// @Summary Add an area covering every service a provider performs
+// @Tags Areas
+// @Accept json
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Param area body models.ServiceArea true "Area data"
+// @Success 201 {object} models.ServiceArea
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /providers/{id}/areas [post]
+func (h *AreaHandler) CreateProviderArea(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.ServiceArea
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	area, err := h.service.CreateProviderArea(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, area)
+}
+
+// ListProviderAreas godoc
+// @Summary List a provider's areas
+// @Tags Areas
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Success 200 {array} models.ServiceArea
+// @Router /providers/{id}/areas [get]
+func (h *AreaHandler) ListProviderAreas(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	areas, err := h.service.ListProviderAreas(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, areas)
+}
+
+// DeleteArea godoc
+// @Summary Delete a service area
+// @Tags Areas
+// @Param id path int true "Area ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /areas/{id} [delete]
+func (h *AreaHandler) DeleteArea(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteArea(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseGeoPoint reads a lat/lng query pair; both must be present.
+func parseGeoPoint(lat, lng string) (*models.GeoPoint, error) {
+	if lat == "" || lng == "" {
+		return nil, errors.New("lat and lng must be given together")
+	}
+	latV, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return nil, err
+	}
+	lngV, err := strconv.ParseFloat(lng, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GeoPoint{Lat: latV, Lng: lngV}, nil
+}
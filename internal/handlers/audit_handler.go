@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	service *services.AuditService
+}
+
+func NewAuditHandler(service *services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListAudit godoc
+// @Summary List recorded changes to categories and services
+// @Tags Audit
+// @Produce json
+// @Param entity query string false "category or service"
+// @Param id query int false "Entity ID; requires entity"
+// @Param actor query string false "Filter by actor"
+// @Param order query string false "desc (default) or asc"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} models.Page[models.AuditEntry]
+// @Failure 400 {object} ErrorResponse
+// @Router /audit [get]
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	filter := models.AuditFilter{EntityType: c.Query("entity"), Actor: c.Query("actor")}
+	if v := c.Query("id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		filter.EntityID = &id
+	}
+
+	entries, err := h.service.ListAudit(c.Request.Context(), filter, opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
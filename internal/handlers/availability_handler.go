@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/availability"
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityHandler struct {
+	service *services.AvailabilityService
+}
+
+func NewAvailabilityHandler(service *services.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{service: service}
+}
+
+// GetWorkingHours godoc
+// @Summary Get a provider's weekly working hours
+// @Tags Availability
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Success 200 {array} models.WorkingHours
+// @Failure 404 {object} ErrorResponse
+// @Router /providers/{id}/working-hours [get]
+func (h *AvailabilityHandler) GetWorkingHours(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	hours, err := h.service.GetWorkingHours(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// SetWorkingHours godoc
+// @Summary Replace a provider's weekly working hours
+// @Description Times are "HH:MM" in the provider's timezone; weekday 0 is Sunday.
+// @Tags Availability
+// @Accept json
+// @Param id path int true "Provider ID"
+// @Param hours body []models.WorkingHours true "Working windows"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/working-hours [put]
+func (h *AvailabilityHandler) SetWorkingHours(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req []models.WorkingHours
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.SetWorkingHours(c.Request.Context(), id, req); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListExceptions godoc
+// @Summary List a provider's date exceptions
+// @Tags Availability
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Param from query string false "First date, YYYY-MM-DD (default today)"
+// @Param to query string false "Last date, YYYY-MM-DD (default one year after from)"
+// @Success 200 {array} models.AvailabilityException
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/exceptions [get]
+func (h *AvailabilityHandler) ListExceptions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	from, to := dateRangeQuery(c)
+	exceptions, err := h.service.ListExceptions(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, exceptions)
+}
+
+// ExceptionRequest is the body used to set the hours of one date.
+type ExceptionRequest struct {
+	Windows []models.HoursWindow `json:"windows"`
+}
+
+// SetException godoc
+// @Summary Override a provider's hours on one date
+// @Description An empty window list marks the date as a day off.
+// @Tags Availability
+// @Accept json
+// @Param id path int true "Provider ID"
+// @Param date path string true "Date, YYYY-MM-DD"
+// @Param exception body ExceptionRequest true "Replacement windows"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/exceptions/{date} [put]
+func (h *AvailabilityHandler) SetException(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	exception := &models.AvailabilityException{
+		ProviderID: id,
+		Date:       c.Param("date"),
+		Windows:    req.Windows,
+	}
+	if err := h.service.SetException(c.Request.Context(), exception); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteException godoc
+// @Summary Remove a date exception, restoring the weekly hours
+// @Tags Availability
+// @Param id path int true "Provider ID"
+// @Param date path string true "Date, YYYY-MM-DD"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/exceptions/{date} [delete]
+func (h *AvailabilityHandler) DeleteException(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteException(c.Request.Context(), id, c.Param("date")); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListBlackouts godoc
+// @Summary List a provider's blackout dates
+// @Tags Availability
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Param from query string false "First date, YYYY-MM-DD (default today)"
+// @Param to query string false "Last date, YYYY-MM-DD (default one year after from)"
+// @Success 200 {array} models.Blackout
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/blackouts [get]
+func (h *AvailabilityHandler) ListBlackouts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	from, to := dateRangeQuery(c)
+	blackouts, err := h.service.ListBlackouts(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, blackouts)
+}
+
+// CreateBlackout godoc
+// @Summary Block out a range of dates for a provider
+// @Tags Availability
+// @Accept json
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Param blackout body models.Blackout true "Blackout dates, inclusive"
+// @Success 201 {object} models.Blackout
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/blackouts [post]
+func (h *AvailabilityHandler) CreateBlackout(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Blackout
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ProviderID = id
+
+	blackout, err := h.service.CreateBlackout(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, blackout)
+}
+
+// DeleteBlackout godoc
+// @Summary Remove a blackout
+// @Tags Availability
+// @Param id path int true "Provider ID"
+// @Param blackoutId path int true "Blackout ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/blackouts/{blackoutId} [delete]
+func (h *AvailabilityHandler) DeleteBlackout(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	blackoutID, err := strconv.ParseInt(c.Param("blackoutId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteBlackout(c.Request.Context(), id, blackoutID); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetServiceAvailability godoc
+// @Summary Compute open booking slots for a service
+// @Description Combines each provider's working hours, exceptions, blackouts and existing bookings. Ranges are limited to 31 days.
+// @Tags Availability
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param from query string true "Range start, RFC 3339"
+// @Param to query string true "Range end, RFC 3339"
+// @Param provider_id query int false "Only this provider"
+// @Param step query int false "Minutes between slot starts (default the service duration)"
+// @Success 200 {array} models.ProviderAvailability
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/availability [get]
+func (h *AvailabilityHandler) GetServiceAvailability(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if c.Query("from") == "" || c.Query("to") == "" {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(errors.New("from and to are required")))
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var providerID *int64
+	if v := c.Query("provider_id"); v != "" {
+		pid, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		providerID = &pid
+	}
+
+	var step time.Duration
+	if v := c.Query("step"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		step = time.Duration(minutes) * time.Minute
+	}
+
+	slots, err := h.service.GetServiceAvailability(c.Request.Context(), id, from, to, providerID, step)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// dateRangeQuery reads the from/to date parameters, defaulting to the year
+// starting today.
+func dateRangeQuery(c *gin.Context) (string, string) {
+	from := c.Query("from")
+	if from == "" {
+		from = time.Now().UTC().Format(availability.DateLayout)
+	}
+	to := c.Query("to")
+	if to == "" {
+		if start, err := time.Parse(availability.DateLayout, from); err == nil {
+			to = start.AddDate(1, 0, 0).Format(availability.DateLayout)
+		}
+	}
+	return from, to
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookingHandler struct {
+	service *services.BookingService
+}
+
+func NewBookingHandler(service *services.BookingService) *BookingHandler {
+	return &BookingHandler{service: service}
+}
+
+// CreateBooking godoc
+// @Summary Request a booking for a service
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param booking body models.Booking true "Booking data"
+// @Success 201 {object} models.Booking
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /bookings [post]
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	var req models.Booking
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	booking, err := h.service.CreateBooking(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, booking)
+}
+
+// GetBooking godoc
+// @Summary Get booking details
+// @Tags Bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.Booking
+// @Failure 404 {object} ErrorResponse
+// @Router /bookings/{id} [get]
+func (h *BookingHandler) GetBooking(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	booking, err := h.service.GetBooking(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// ListBookings godoc
+// @Summary List bookings
+// @Tags Bookings
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param sort query string false "Sort key: starts_at, id or created_at"
+// @Param order query string false "asc or desc"
+// @Param service_id query int false "Filter by service"
+// @Param provider_id query int false "Filter by provider"
+// @Param status query string false "Filter by status"
+// @Param from query string false "Only bookings ending after this RFC 3339 time"
+// @Param to query string false "Only bookings starting before this RFC 3339 time"
+// @Success 200 {object} models.Page[models.Booking]
+// @Failure 400 {object} ErrorResponse
+// @Router /bookings [get]
+func (h *BookingHandler) ListBookings(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	filter := models.BookingFilter{Status: c.Query("status")}
+	for param, dst := range map[string]**int64{
+		"service_id":  &filter.ServiceID,
+		"provider_id": &filter.ProviderID,
+	} {
+		if v := c.Query(param); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+				return
+			}
+			*dst = &id
+		}
+	}
+	for param, dst := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+				return
+			}
+			*dst = &t
+		}
+	}
+
+	bookings, err := h.service.ListBookings(c.Request.Context(), filter, opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, bookings)
+}
+
+// BookingTransitionRequest is the body used to change a booking's status.
+type BookingTransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// TransitionBooking godoc
+// @Summary Move a booking to a new status
+// @Description Allowed: requested → confirmed|cancelled, confirmed → in_progress|cancelled, in_progress → completed.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param transition body BookingTransitionRequest true "Target status"
+// @Success 200 {object} models.Booking
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /bookings/{id}/transitions [post]
+func (h *BookingHandler) TransitionBooking(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req BookingTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	booking, err := h.service.TransitionBooking(c.Request.Context(), id, req.Status, req.Note)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// ListBookingEvents godoc
+// @Summary Get the status history of a booking
+// @Tags Bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {array} models.BookingEvent
+// @Failure 404 {object} ErrorResponse
+// @Router /bookings/{id}/events [get]
+func (h *BookingHandler) ListBookingEvents(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	events, err := h.service.ListBookingEvents(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
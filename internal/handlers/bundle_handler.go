@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleHandler struct {
+	service *services.BundleService
+}
+
+func NewBundleHandler(service *services.BundleService) *BundleHandler {
+	return &BundleHandler{service: service}
+}
+
+// CreateBundle godoc
+// @Summary Create a bundle of services
+// @Description price_rule is fixed, with price, or discount, with discount_bp basis points off the
+// @Description sum of the service prices. An active bundle may only contain active services, all
+// @Description priced in the same currency.
+// @Tags Bundles
+// @Accept json
+// @Produce json
+// @Param bundle body models.Bundle true "Bundle data"
+// @Success 201 {object} models.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Router /bundles [post]
+func (h *BundleHandler) CreateBundle(c *gin.Context) {
+	var req models.Bundle
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	bundle, err := h.service.CreateBundle(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, bundle)
+}
+
+// ListBundles godoc
+// @Summary List bundles
+// @Tags Bundles
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param sort query string false "Sort key: name, id or created_at"
+// @Param order query string false "asc or desc"
+// @Param is_active query bool false "Filter by active state"
+// @Param category_id query int false "Filter by category"
+// @Success 200 {object} models.Page[models.Bundle]
+// @Failure 400 {object} ErrorResponse
+// @Router /bundles [get]
+func (h *BundleHandler) ListBundles(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	if err := parseCategoryFilter(c, &opts); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	bundles, err := h.service.ListBundles(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, bundles)
+}
+
+// GetBundle godoc
+// @Summary Get a bundle with its services and prices
+// @Tags Bundles
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Success 200 {object} models.Bundle
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [get]
+func (h *BundleHandler) GetBundle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	bundle, err := h.service.GetBundle(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// UpdateBundle godoc
+// @Summary Replace a bundle and its services
+// @Description Activating a bundle clears the reason it was deactivated for.
+// @Tags Bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "Bundle ID"
+// @Param bundle body models.Bundle true "Bundle data"
+// @Success 200 {object} models.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [put]
+func (h *BundleHandler) UpdateBundle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Bundle
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ID = id
+
+	bundle, err := h.service.UpdateBundle(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, bundle)
+}
+
+// DeleteBundle godoc
+// @Summary Delete a bundle, leaving its services as they are
+// @Tags Bundles
+// @Param id path int true "Bundle ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /bundles/{id} [delete]
+func (h *BundleHandler) DeleteBundle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteBundle(c.Request.Context(), id); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListBundlesByCategory godoc
+// @Summary List the bundles of a category
+// @Tags Bundles
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param include_descendants query bool false "Include bundles of all nested categories"
+// @Param is_active query bool false "Filter by active state"
+// @Success 200 {array} models.Bundle
+// @Failure 400 {object} ErrorResponse
+// @Router /categories/{id}/bundles [get]
+func (h *BundleHandler) ListBundlesByCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	includeDescendants := false
+	if v := c.Query("include_descendants"); v != "" {
+		if includeDescendants, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+	}
+	var isActive *bool
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid is_active %q", v)))
+			return
+		}
+		isActive = &active
+	}
+
+	bundles, err := h.service.ListBundlesByCategory(c.Request.Context(), categoryID, includeDescendants, isActive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, bundles)
+}
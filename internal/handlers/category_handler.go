@@ -3,6 +3,8 @@ package handlers
 import (
 	"server/internal/models"
 	"server/internal/services"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -19,6 +21,8 @@ func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
 
 // CreateCategory godoc
 // @Summary Create a new service category
+// @Description publish_at and unpublish_at schedule when the item goes live or is taken down;
+// @Description they must lie in the future and are cleared once applied.
 // @Tags Categories
 // @Accept json
 // @Produce json
@@ -39,43 +43,84 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag(category.Version))
 	c.JSON(http.StatusCreated, category)
 }
 
 // GetCategory godoc
 // @Summary Get category details
+// @Description Serves the published category. Admins may ask for its draft instead.
 // @Tags Categories
 // @Produce json
-// @Param id path int true "Category ID"
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param If-None-Match header string false "ETag of a copy already held"
+// @Param id path string true "Category ID or slug"
+// @Param revision query string false "published (default) or draft; draft needs the admin bearer token"
 // @Success 200 {object} models.Category
+// @Success 304
+// @Failure 301 {object} MovedResponse "The slug was retired; Location has the current one"
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /categories/{id} [get]
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+	draft, ok := draftRequested(c)
+	if !ok {
 		return
 	}
 
-	category, err := h.service.GetCategory(c.Request.Context(), id)
+	var category *models.Category
+	ref := c.Param("id")
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err == nil {
+		category, err = h.service.GetCategory(localeContext(c), id)
+	} else {
+		category, err = h.service.GetCategoryBySlug(localeContext(c), ref)
+	}
 	if err != nil {
+		if redirectMoved(c, "/categories/", err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, NewErrorResponse(err))
 		return
 	}
+	if category != nil && draft {
+		if category, err = h.service.ApplyCategoryDraft(c.Request.Context(), category); err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, category)
+		return
+	}
+	if category != nil && notModified(c, category.Version) {
+		return
+	}
 
 	c.JSON(http.StatusOK, category)
 }
 
 // ListCategories godoc
-// @Summary List all categories
+// @Summary List categories
 // @Tags Categories
 // @Produce json
-// @Success 200 {array} models.Category
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param sort query string false "Sort key: sort_order (default, manual order then name), name, id or created_at"
+// @Param order query string false "asc or desc"
+// @Param is_active query bool false "Filter by active state"
+// @Success 200 {object} models.Page[models.Category]
+// @Failure 400 {object} ErrorResponse
 // @Router /categories [get]
 func (h *CategoryHandler) ListCategories(c *gin.Context) {
-	categories, err := h.service.ListCategories(c.Request.Context())
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	categories, err := h.service.ListCategories(localeContext(c), opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
 		return
 	}
 
@@ -83,14 +128,20 @@ func (h *CategoryHandler) ListCategories(c *gin.Context) {
 }
 
 // UpdateCategory godoc
-// @Summary Update category details
+// @Summary Save category details as its draft
+// @Description The draft replaces any earlier one and goes live once published; until then the
+// @Description category is served as before.
 // @Tags Categories
 // @Accept json
 // @Produce json
+// @Param If-Match header string true "ETag of the live version being edited, or *"
 // @Param id path int true "Category ID"
 // @Param category body models.Category true "Category data"
-// @Success 204
+// @Success 200 {object} models.Revision
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
 // @Router /categories/{id} [put]
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -105,21 +156,160 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 	req.ID = id
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	req.Version = version
+
+	draft, err := h.service.SaveCategoryDraft(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(req.Version))
+	c.JSON(http.StatusOK, draft)
+}
+
+// ReorderCategories godoc
+// @Summary Set the display order of the top-level categories
+// @Description The IDs must list every top-level category not in the trash exactly once. Lists
+// @Description sort by this order unless asked otherwise.
+// @Tags Categories
+// @Accept json
+// @Param order body ReorderRequest true "Category IDs, first to last"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The IDs do not match the current top-level categories"
+// @Router /categories/reorder [put]
+func (h *CategoryHandler) ReorderCategories(c *gin.Context) {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.ReorderCategories(c.Request.Context(), nil, req.IDs); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
 
-	if err := h.service.UpdateCategory(c.Request.Context(), &req); err != nil {
+// ReorderChildCategories godoc
+// @Summary Set the display order of a category's children
+// @Description The IDs must list every child of the category not in the trash exactly once.
+// @Tags Categories
+// @Accept json
+// @Param id path int true "Category ID"
+// @Param order body ReorderRequest true "Category IDs, first to last"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The IDs do not match the category's current children"
+// @Router /categories/{id}/children/reorder [put]
+func (h *CategoryHandler) ReorderChildCategories(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
 		return
 	}
 
+	if err := h.service.ReorderCategories(c.Request.Context(), &id, req.IDs); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
+// PatchCategory godoc
+// @Summary Partially update the draft of a category
+// @Description Takes a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902), chosen by
+// @Description Content-Type, applied to the draft or, without one, the live category. The patched
+// @Description category is validated, saved as the draft and returned.
+// @Tags Categories
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param If-Match header string true "ETag of the live version being edited, or *"
+// @Param id path int true "Category ID"
+// @Param patch body object true "Patch document"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "A JSON Patch test operation failed"
+// @Failure 412 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /categories/{id} [patch]
+func (h *CategoryHandler) PatchCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	p, ok := parsePatch(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.PatchCategoryDraft(c.Request.Context(), id, version, p)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(category.Version))
+	c.JSON(http.StatusOK, category)
+}
+
+// DependentsResponse answers a delete of a category that still has live
+// subcategories, services or bundles, listing them.
+type DependentsResponse struct {
+	Error         string         `json:"error"`
+	Subcategories []DependentRef `json:"subcategories"`
+	Services      []DependentRef `json:"services"`
+	Bundles       []DependentRef `json:"bundles"`
+}
+
+// DependentRef names a dependent. Bundles have no slug.
+type DependentRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
 // DeleteCategory godoc
 // @Summary Delete a category
+// @Description A category with live subcategories, services or bundles is only deleted if
+// @Description reassign_to or cascade says what becomes of them; otherwise the answer is 409,
+// @Description listing them. Cascading deletes the bundles outright.
 // @Tags Categories
+// @Produce json
+// @Param If-Match header string true "ETag of the version being deleted, or *"
 // @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category to move the subcategories, services and bundles to"
+// @Param cascade query bool false "Trash the subcategories and services and delete the bundles too; needs the admin bearer token"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} DependentsResponse
+// @Failure 412 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
 // @Router /categories/{id} [delete]
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -128,10 +318,287 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteCategory(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+	var opts models.CategoryDeleteOptions
+	if v := c.Query("reassign_to"); v != "" {
+		target, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid reassign_to %q", v)))
+			return
+		}
+		opts.ReassignTo = &target
+	}
+	if v := c.Query("cascade"); v != "" {
+		if opts.Cascade, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid cascade %q", v)))
+			return
+		}
+		if opts.Cascade && !isAdmin(c) {
+			c.JSON(http.StatusForbidden, NewErrorResponse(errAdminOnly))
+			return
+		}
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCategory(c.Request.Context(), id, version, opts); err != nil {
+		var dependents *services.DependentsError
+		if errors.As(err, &dependents) {
+			c.JSON(http.StatusConflict, newDependentsResponse(dependents))
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
 		return
 	}
 
 	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+func newDependentsResponse(err *services.DependentsError) DependentsResponse {
+	resp := DependentsResponse{
+		Error:         err.Error(),
+		Subcategories: make([]DependentRef, len(err.Subcategories)),
+		Services:      make([]DependentRef, len(err.Services)),
+		Bundles:       make([]DependentRef, len(err.Bundles)),
+	}
+	for i, c := range err.Subcategories {
+		resp.Subcategories[i] = DependentRef{ID: c.ID, Name: c.Name, Slug: c.Slug}
+	}
+	for i, s := range err.Services {
+		resp.Services[i] = DependentRef{ID: s.ID, Name: s.Name, Slug: s.Slug}
+	}
+	for i, b := range err.Bundles {
+		resp.Bundles[i] = DependentRef{ID: b.ID, Name: b.Name}
+	}
+	return resp
+}
+
+// RestoreCategory godoc
+// @Summary Restore a deleted category from the trash
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} models.Category
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /categories/{id}/restore [post]
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	category, err := h.service.RestoreCategory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(category.Version))
+	c.JSON(http.StatusOK, category)
+}
+
+// PublishCategory godoc
+// @Summary Publish the draft of a category
+// @Description Makes the draft live, where customers see it, and its published revision.
+// @Description Only the fields the draft edits are written, so changes made to the category since
+// @Description the draft was saved are kept; when the draft edits one of the same fields the
+// @Description answer is 412. Needs the admin bearer token.
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The category has no draft"
+// @Failure 412 {object} ErrorResponse "The draft and the live category both changed a field"
+// @Router /categories/{id}/publish [post]
+func (h *CategoryHandler) PublishCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	category, err := h.service.PublishCategory(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(category.Version))
+	c.JSON(http.StatusOK, category)
+}
+
+// ListCategoryRevisions godoc
+// @Summary List the revisions of a category, newest first
+// @Description Needs the admin bearer token.
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Revision
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/revisions [get]
+func (h *CategoryHandler) ListCategoryRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	revisions, err := h.service.ListCategoryRevisions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffCategoryRevisions godoc
+// @Summary Compare two revisions of a category
+// @Description Needs the admin bearer token.
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param from query int false "Revision number (default the published revision)"
+// @Param to query int false "Revision number (default the draft)"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/revisions/diff [get]
+func (h *CategoryHandler) DiffCategoryRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	from, to, ok := diffRange(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.DiffCategoryRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RollbackCategory godoc
+// @Summary Roll a category back to an earlier revision
+// @Description Makes the revision live again as a new published revision. The category's
+// @Description schedule and any draft are kept. Needs the admin bearer token.
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The revision is the draft"
+// @Router /categories/{id}/revisions/{number}/rollback [post]
+func (h *CategoryHandler) RollbackCategory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	number, ok := revisionNumber(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.RollbackCategory(c.Request.Context(), id, number)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(category.Version))
+	c.JSON(http.StatusOK, category)
+}
+
+// GetCategoryAncestors godoc
+// @Summary Get the breadcrumb of parent categories, root first
+// @Tags Categories
+// @Produce json
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Category
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/ancestors [get]
+func (h *CategoryHandler) GetCategoryAncestors(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	ancestors, err := h.service.GetCategoryAncestors(localeContext(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, ancestors)
+}
+
+// ListChildCategories godoc
+// @Summary List direct child categories
+// @Tags Categories
+// @Produce json
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Category
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/children [get]
+func (h *CategoryHandler) ListChildCategories(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	children, err := h.service.ListChildCategories(localeContext(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, children)
+}
+
+// GetCategoryTree godoc
+// @Summary Get a category and all of its descendants as nested JSON
+// @Tags Categories
+// @Produce json
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param id path int true "Category ID"
+// @Success 200 {object} models.CategoryNode
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	tree, err := h.service.GetCategoryTree(localeContext(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tree)
+}
@@ -1,9 +1,38 @@
 package handlers
 
+import (
+    "errors"
+    "net/http"
+
+    "server/internal/services"
+)
+
 type ErrorResponse struct {
     Error string `json:"error"`
 }
 
 func NewErrorResponse(err error) ErrorResponse {
     return ErrorResponse{Error: err.Error()}
-}
\ No newline at end of file
+}
+
+// errorStatus maps the sentinel errors returned by services to an HTTP
+// status, falling back to the given status for anything else.
+func errorStatus(err error, fallback int) int {
+    switch {
+    case errors.Is(err, services.ErrNotFound):
+        return http.StatusNotFound
+    case errors.Is(err, services.ErrConflict):
+        return http.StatusConflict
+    case errors.Is(err, services.ErrForbidden):
+        return http.StatusForbidden
+    case errors.Is(err, services.ErrPreconditionFailed):
+        return http.StatusPreconditionFailed
+    case errors.Is(err, services.ErrInvalidListOptions):
+        return http.StatusBadRequest
+    case errors.Is(err, services.ErrTooLarge):
+        return http.StatusRequestEntityTooLarge
+    case errors.Is(err, services.ErrUnsupportedMediaType):
+        return http.StatusUnsupportedMediaType
+    }
+    return fallback
+}
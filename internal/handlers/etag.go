@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag is the entity tag served for a version of an entity.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// notModified sets the ETag of a version of an entity and reports whether
+// the client's If-None-Match already names it, in which case it responds
+// 304 Not Modified.
+func notModified(c *gin.Context, version int64) bool {
+	tag := etag(version)
+	c.Header("ETag", tag)
+
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == tag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion returns the version of an entity a write must apply to,
+// taken from If-Match. "*" gives 0, which matches any version. On failure it
+// responds 428 when the header is missing, 412 when it only names weak tags,
+// which never match a write, and 400 when it is malformed.
+func ifMatchVersion(c *gin.Context) (int64, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, NewErrorResponse(errors.New("If-Match header is required")))
+		return 0, false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return 0, true
+	}
+
+	var versions []int64
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if strings.HasPrefix(t, "W/") {
+			continue
+		}
+		v, err := strconv.ParseInt(strings.Trim(t, `"`), 10, 64)
+		if err != nil || len(t) < 2 || t[0] != '"' || t[len(t)-1] != '"' || v <= 0 {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid If-Match entity tag %s", t)))
+			return 0, false
+		}
+		versions = append(versions, v)
+	}
+
+	switch len(versions) {
+	case 0:
+		c.JSON(http.StatusPreconditionFailed, NewErrorResponse(errors.New("weak entity tags cannot match a write")))
+		return 0, false
+	case 1:
+		return versions[0], true
+	default:
+		c.JSON(http.StatusBadRequest, NewErrorResponse(errors.New("If-Match must name a single entity tag")))
+		return 0, false
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"time"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportCatalog godoc
+// @Summary Download the catalog
+// @Description Streams categories, parents first, and then services as rows with the columns
+// @Description POST /import reads, so an export can be imported again.
+// @Tags Import
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default), ndjson or xlsx"
+// @Param is_active query bool false "Filter categories and services by active status"
+// @Param category_id query int false "Only export services of this category"
+// @Param postal_code query string false "Only export services offered in this postal code"
+// @Param lat query number false "Only export services offered at this latitude (requires lng)"
+// @Param lng query number false "Only export services offered at this longitude (requires lat)"
+// @Param tags query string false "Only export services with these comma-separated tag slugs"
+// @Param tag_match query string false "any (default) or all of the tags"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Router /export [get]
+func (h *ExportHandler) ExportCatalog(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	if err := parseServiceFilters(c, &opts); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	format := c.DefaultQuery("format", models.FormatCSV)
+	contentType, extension, err := h.service.ExportType(format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	filename := "catalog-" + time.Now().UTC().Format("20060102-150405") + "." + extension
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	if err := h.service.Export(c.Request.Context(), c.Writer, format, opts); err != nil {
+		if c.Writer.Written() {
+			// The status line is gone; all that is left is to cut the
+			// download short and log why.
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+
+	"server/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeContext returns the request context carrying the locales negotiated
+// from Accept-Language, so services return translated content.
+func localeContext(c *gin.Context) context.Context {
+	c.Header("Vary", "Accept-Language")
+	return i18n.WithLocales(c.Request.Context(), i18n.Chain(c.GetHeader("Accept-Language")))
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBytes bounds the size of an import upload.
+const maxImportBytes = 10 << 20
+
+// importFormats maps the content types accepted by imports to formats.
+var importFormats = map[string]string{
+	"text/csv":             models.FormatCSV,
+	"application/x-ndjson": models.FormatNDJSON,
+	"application/ndjson":   models.FormatNDJSON,
+}
+
+type ImportHandler struct {
+	service *services.ImportService
+}
+
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// ImportCatalog godoc
+// @Summary Create or update categories and services in bulk
+// @Description Rows are CSV with a header row, or NDJSON objects, with the fields type (category
+// @Description or service), name, description, is_active, parent (category name), category
+// @Description (category name), duration_minutes, price, currency, pricing_unit, min_price and
+// @Description max_price. Rows match existing entities by name; unset fields keep their value.
+// @Tags Import
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param format query string false "csv or ndjson; taken from Content-Type by default"
+// @Param mode query string false "atomic (default) keeps nothing if a row fails; best_effort keeps the rows that succeed"
+// @Param dry_run query bool false "Validate and report without writing"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 422 {object} models.ImportReport "An atomic import had failing rows and was rolled back"
+// @Router /import [post]
+func (h *ImportHandler) ImportCatalog(c *gin.Context) {
+	opts := models.ImportOptions{Format: c.Query("format"), Mode: c.Query("mode")}
+	if opts.Format == "" {
+		opts.Format = importFormats[c.ContentType()]
+	}
+	if v := c.Query("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid dry_run %q", v)))
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, NewErrorResponse(
+				fmt.Errorf("imports are limited to %d bytes", maxImportBytes)))
+			return
+		}
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	report, err := h.service.Import(c.Request.Context(), bytes.NewReader(body), opts)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Committed && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, report)
+}
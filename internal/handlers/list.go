@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseListOptions reads the pagination, sort and common filter query
+// parameters shared by all list endpoints.
+func parseListOptions(c *gin.Context) (models.ListOptions, error) {
+	opts := models.ListOptions{
+		Cursor: c.Query("cursor"),
+		Sort:   c.Query("sort"),
+		Order:  c.Query("order"),
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid limit %q", v)
+		}
+		opts.Limit = limit
+	}
+
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, fmt.Errorf("invalid is_active %q", v)
+		}
+		opts.IsActive = &active
+	}
+
+	return opts, nil
+}
+
+// ReorderRequest is the new display order of a set of items, first to last.
+type ReorderRequest struct {
+	IDs []int64 `json:"ids" binding:"required"`
+}
+
+// parseCategoryFilter reads the category_id query parameter into opts.
+func parseCategoryFilter(c *gin.Context, opts *models.ListOptions) error {
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid category_id %q", v)
+		}
+		opts.CategoryID = &categoryID
+	}
+	return nil
+}
+
+// parseServiceFilters reads the query parameters narrowing service lists
+// into opts.
+func parseServiceFilters(c *gin.Context, opts *models.ListOptions) error {
+	if err := parseCategoryFilter(c, opts); err != nil {
+		return err
+	}
+	opts.PostalCode = c.Query("postal_code")
+	if lat, lng := c.Query("lat"), c.Query("lng"); lat != "" || lng != "" {
+		near, err := parseGeoPoint(lat, lng)
+		if err != nil {
+			return err
+		}
+		opts.Near = near
+	}
+	parseTagFilter(c, opts)
+	return nil
+}
+
+// parseTagFilter reads the comma-separated tag slugs of ?tags and how they
+// combine from ?tag_match into opts.
+func parseTagFilter(c *gin.Context, opts *models.ListOptions) {
+	if v := c.Query("tags"); v != "" {
+		opts.Tags = strings.Split(v, ",")
+	}
+	opts.TagMatch = c.Query("tag_match")
+}
+
+// listErrorStatus maps errors returned by list services to an HTTP status.
+func listErrorStatus(err error) int {
+	return errorStatus(err, http.StatusInternalServerError)
+}
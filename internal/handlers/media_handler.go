@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead allows for the form fields and part headers sent
+// alongside an uploaded file.
+const multipartOverhead = 64 << 10
+
+type MediaHandler struct {
+	service *services.MediaService
+}
+
+func NewMediaHandler(service *services.MediaService) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+// UploadServiceMedia godoc
+// @Summary Upload an image for a service
+// @Description The file type is detected from its content; JPEG, PNG and GIF are accepted.
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param file formData file true "Image file"
+// @Param alt_text formData string false "Alternative text"
+// @Param is_primary formData bool false "Make this the primary image"
+// @Success 201 {object} models.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Router /services/{id}/media [post]
+func (h *MediaHandler) UploadServiceMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	h.upload(c, &models.Media{ServiceID: &id})
+}
+
+// UploadCategoryMedia godoc
+// @Summary Upload an image for a category
+// @Description The file type is detected from its content; JPEG, PNG and GIF are accepted.
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param file formData file true "Image file"
+// @Param alt_text formData string false "Alternative text"
+// @Param is_primary formData bool false "Make this the primary image"
+// @Success 201 {object} models.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Router /categories/{id}/media [post]
+func (h *MediaHandler) UploadCategoryMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	h.upload(c, &models.Media{CategoryID: &id})
+}
+
+func (h *MediaHandler) upload(c *gin.Context, req *models.Media) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxUploadBytes()+multipartOverhead)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, NewErrorResponse(
+				fmt.Errorf("uploads are limited to %d bytes", h.service.MaxUploadBytes())))
+			return
+		}
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	req.AltText = c.PostForm("alt_text")
+	if v := c.PostForm("is_primary"); v != "" {
+		if req.IsPrimary, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid is_primary %q", v)))
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	media, err := h.service.UploadMedia(c.Request.Context(), req, file)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// ListServiceMedia godoc
+// @Summary List a service's images in display order
+// @Tags Media
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.Media
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/media [get]
+func (h *MediaHandler) ListServiceMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	media, err := h.service.ListServiceMedia(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// ListCategoryMedia godoc
+// @Summary List a category's images in display order
+// @Tags Media
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Media
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/media [get]
+func (h *MediaHandler) ListCategoryMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	media, err := h.service.ListCategoryMedia(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// MediaUpdateRequest is the body used to change an image's metadata.
+type MediaUpdateRequest struct {
+	AltText   string `json:"alt_text"`
+	Position  int    `json:"position"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// UpdateMedia godoc
+// @Summary Update an image's alt text, position or primary flag
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path int true "Media ID"
+// @Param media body MediaUpdateRequest true "Media metadata"
+// @Success 200 {object} models.Media
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id} [put]
+func (h *MediaHandler) UpdateMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req MediaUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	media, err := h.service.UpdateMedia(c.Request.Context(), id, req.AltText, req.Position, req.IsPrimary)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DeleteMedia godoc
+// @Summary Delete an image
+// @Tags Media
+// @Param id path int true "Media ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id} [delete]
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteMedia(c.Request.Context(), id); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMediaFile godoc
+// @Summary Download an image
+// @Tags Media
+// @Produce image/jpeg,image/png,image/gif
+// @Param id path int true "Media ID"
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id}/file [get]
+func (h *MediaHandler) GetMediaFile(c *gin.Context) {
+	h.serve(c, false)
+}
+
+// GetMediaThumbnail godoc
+// @Summary Download an image's thumbnail
+// @Tags Media
+// @Produce image/jpeg,image/png
+// @Param id path int true "Media ID"
+// @Success 200
+// @Failure 404 {object} ErrorResponse
+// @Router /media/{id}/thumbnail [get]
+func (h *MediaHandler) GetMediaThumbnail(c *gin.Context) {
+	h.serve(c, true)
+}
+
+func (h *MediaHandler) serve(c *gin.Context, thumbnail bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	file, contentType, err := h.service.OpenMedia(c.Request.Context(), id, thumbnail)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	// Blob keys are never reused, so the content behind an ID never changes.
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.DataFromReader(http.StatusOK, -1, contentType, file, nil)
+}
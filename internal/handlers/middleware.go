@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"server/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxHeaderIDLength bounds the actor and request ID taken from headers.
+const maxHeaderIDLength = 128
+
+// RequestContext attaches the actor named by X-Actor and a request ID to the
+// request context, so the changes a request makes can be attributed to it.
+// Nothing vouches for X-Actor, so AdminAuth replaces it on admin requests.
+// The request ID is taken from X-Request-ID when the client supplies a
+// usable one and is echoed back in the response.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := headerID(c.GetHeader("X-Request-ID"))
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header("X-Request-ID", requestID)
+
+		actor := headerID(c.GetHeader("X-Actor"))
+		c.Request = c.Request.WithContext(audit.WithRequest(c.Request.Context(), actor, requestID))
+		c.Next()
+	}
+}
+
+// adminKey marks requests made by an admin in the gin context.
+const adminKey = "admin"
+
+var errAdminOnly = errors.New("only admins may do this")
+
+// AdminAuth marks requests that carry token as a bearer token in their
+// Authorization header as made by an admin, in the gin context and for the
+// audit log. With an empty token no request is. It must run after
+// RequestContext.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok && token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+			c.Set(adminKey, true)
+			c.Request = c.Request.WithContext(audit.WithAdmin(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects requests not made by an admin with 403 Forbidden.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdmin(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, NewErrorResponse(errAdminOnly))
+			return
+		}
+		c.Next()
+	}
+}
+
+func isAdmin(c *gin.Context) bool {
+	return c.GetBool(adminKey)
+}
+
+// headerID returns a header value fit to be stored as an identifier, or ""
+// when it is too long or contains anything but printable ASCII.
+func headerID(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > maxHeaderIDLength {
+		return ""
+	}
+	for i := 0; i < len(v); i++ {
+		if v[i] < 0x20 || v[i] > 0x7e {
+			return ""
+		}
+	}
+	return v
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OptionHandler struct {
+	service *services.OptionService
+}
+
+func NewOptionHandler(service *services.OptionService) *OptionHandler {
+	return &OptionHandler{service: service}
+}
+
+// ListOptionGroups godoc
+// @Summary List the option groups of a service with their options
+// @Tags Options
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.OptionGroup
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/option-groups [get]
+func (h *OptionHandler) ListOptionGroups(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	groups, err := h.service.ListOptionGroups(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateOptionGroup godoc
+// @Summary Add an option group to a service
+// @Description kind is single, multi or quantity. min_select and max_select bound the options
+// @Description picked or, for quantity groups, their total count. Options may be listed inline.
+// @Tags Options
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param group body models.OptionGroup true "Option group data"
+// @Success 201 {object} models.OptionGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/option-groups [post]
+func (h *OptionHandler) CreateOptionGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.OptionGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	group, err := h.service.CreateOptionGroup(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateOptionGroup godoc
+// @Summary Update the settings of an option group
+// @Description Options listed in the body are ignored; they have their own endpoints.
+// @Tags Options
+// @Accept json
+// @Produce json
+// @Param id path int true "Option group ID"
+// @Param group body models.OptionGroup true "Option group data"
+// @Success 200 {object} models.OptionGroup
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /option-groups/{id} [put]
+func (h *OptionHandler) UpdateOptionGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.OptionGroup
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ID = id
+
+	group, err := h.service.UpdateOptionGroup(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteOptionGroup godoc
+// @Summary Delete an option group with its options
+// @Tags Options
+// @Param id path int true "Option group ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /option-groups/{id} [delete]
+func (h *OptionHandler) DeleteOptionGroup(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteOptionGroup(c.Request.Context(), id); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateOption godoc
+// @Summary Add an option to an option group
+// @Description modifier_type is fixed, in minor units of the service currency, or percent, in
+// @Description basis points of the base price. Negative amounts are discounts.
+// @Tags Options
+// @Accept json
+// @Produce json
+// @Param id path int true "Option group ID"
+// @Param option body models.Option true "Option data"
+// @Success 201 {object} models.Option
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /option-groups/{id}/options [post]
+func (h *OptionHandler) CreateOption(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Option
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	option, err := h.service.CreateOption(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, option)
+}
+
+// UpdateOption godoc
+// @Summary Update an option
+// @Tags Options
+// @Accept json
+// @Produce json
+// @Param id path int true "Option ID"
+// @Param option body models.Option true "Option data"
+// @Success 200 {object} models.Option
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /options/{id} [put]
+func (h *OptionHandler) UpdateOption(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Option
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ID = id
+
+	option, err := h.service.UpdateOption(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, option)
+}
+
+// DeleteOption godoc
+// @Summary Delete an option
+// @Tags Options
+// @Param id path int true "Option ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /options/{id} [delete]
+func (h *OptionHandler) DeleteOption(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteOption(c.Request.Context(), id); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// QuoteService godoc
+// @Summary Price a configuration of a service
+// @Description Checks the selected options against the service's option groups and returns the
+// @Description base price times units, a line per option and the total, which is never below
+// @Description zero. units is for services not priced flat.
+// @Tags Options
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param quote body models.QuoteRequest true "Configuration"
+// @Success 200 {object} models.Quote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/quote [post]
+func (h *OptionHandler) QuoteService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	quote, err := h.service.Quote(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"server/internal/patch"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePatch reads a patch document of the type named by Content-Type from
+// the request body. On failure it responds 415 for unsupported types and 400
+// for malformed documents.
+func parsePatch(c *gin.Context) (patch.Patch, bool) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return nil, false
+	}
+
+	p, err := patch.Parse(c.ContentType(), body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, patch.ErrUnsupportedType) {
+			status = http.StatusUnsupportedMediaType
+		}
+		c.JSON(status, NewErrorResponse(err))
+		return nil, false
+	}
+	return p, true
+}
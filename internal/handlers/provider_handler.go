@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProviderHandler struct {
+	service *services.ProviderService
+}
+
+func NewProviderHandler(service *services.ProviderService) *ProviderHandler {
+	return &ProviderHandler{service: service}
+}
+
+// CreateProvider godoc
+// @Summary Create a new service provider
+// @Tags Providers
+// @Accept json
+// @Produce json
+// @Param provider body models.Provider true "Provider data"
+// @Success 201 {object} models.Provider
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Another provider has the email"
+// @Router /providers [post]
+func (h *ProviderHandler) CreateProvider(c *gin.Context) {
+	var req models.Provider
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	provider, err := h.service.CreateProvider(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, provider)
+}
+
+// GetProvider godoc
+// @Summary Get provider details
+// @Tags Providers
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Success 200 {object} models.Provider
+// @Failure 404 {object} ErrorResponse
+// @Router /providers/{id} [get]
+func (h *ProviderHandler) GetProvider(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	provider, err := h.service.GetProvider(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, provider)
+}
+
+// ListProviders godoc
+// @Summary List providers
+// @Tags Providers
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param sort query string false "Sort key: name, id or created_at"
+// @Param order query string false "asc or desc"
+// @Param is_active query bool false "Filter by active state"
+// @Success 200 {object} models.Page[models.Provider]
+// @Failure 400 {object} ErrorResponse
+// @Router /providers [get]
+func (h *ProviderHandler) ListProviders(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	providers, err := h.service.ListProviders(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, providers)
+}
+
+// UpdateProvider godoc
+// @Summary Update provider details
+// @Tags Providers
+// @Accept json
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Param provider body models.Provider true "Provider data"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Another provider has the email"
+// @Router /providers/{id} [put]
+func (h *ProviderHandler) UpdateProvider(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Provider
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ID = id
+
+	if err := h.service.UpdateProvider(c.Request.Context(), &req); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteProvider godoc
+// @Summary Delete a provider
+// @Tags Providers
+// @Param id path int true "Provider ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id} [delete]
+func (h *ProviderHandler) DeleteProvider(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteProvider(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListProviderServices godoc
+// @Summary List the services a provider performs
+// @Tags Providers
+// @Produce json
+// @Param id path int true "Provider ID"
+// @Success 200 {array} models.ServiceOffer
+// @Failure 404 {object} ErrorResponse
+// @Router /providers/{id}/services [get]
+func (h *ProviderHandler) ListProviderServices(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	offers, err := h.service.ListProviderServices(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// ProviderServiceRequest is the body used to link a provider to a service.
+type ProviderServiceRequest struct {
+	PriceOverride *int64 `json:"price_override"`
+}
+
+// AttachProviderService godoc
+// @Summary Link a provider to a service, with an optional price override
+// @Tags Providers
+// @Accept json
+// @Param id path int true "Provider ID"
+// @Param serviceId path int true "Service ID"
+// @Param link body ProviderServiceRequest false "Price override in minor units"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/services/{serviceId} [put]
+func (h *ProviderHandler) AttachProviderService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	serviceID, err := strconv.ParseInt(c.Param("serviceId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ProviderServiceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+	}
+
+	if err := h.service.AttachService(c.Request.Context(), id, serviceID, req.PriceOverride); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DetachProviderService godoc
+// @Summary Unlink a provider from a service
+// @Tags Providers
+// @Param id path int true "Provider ID"
+// @Param serviceId path int true "Service ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Router /providers/{id}/services/{serviceId} [delete]
+func (h *ProviderHandler) DetachProviderService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	serviceID, err := strconv.ParseInt(c.Param("serviceId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DetachService(c.Request.Context(), id, serviceID); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListServiceProviders godoc
+// @Summary List the providers that perform a service
+// @Tags Providers
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.ProviderOffer
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/providers [get]
+func (h *ProviderHandler) ListServiceProviders(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	offers, err := h.service.ListServiceProviders(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	service *services.ReviewService
+}
+
+func NewReviewHandler(service *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// CreateReview godoc
+// @Summary Post a review of a service
+// @Description New reviews are pending until moderated. One review per customer per service.
+// @Description The response carries the edit_token the author needs to edit the review; it is
+// @Description not shown again.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param review body models.Review true "Review data"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /services/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Review
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ServiceID = id
+
+	review, err := h.service.CreateReview(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// ListServiceReviews godoc
+// @Summary List a service's reviews
+// @Tags Reviews
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param status query string false "Moderation status (default approved)"
+// @Param sort query string false "recent (default) or helpful"
+// @Param order query string false "desc (default) or asc"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} models.Page[models.Review]
+// @Failure 400 {object} ErrorResponse
+// @Router /services/{id}/reviews [get]
+func (h *ReviewHandler) ListServiceReviews(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	filter := models.ReviewFilter{ServiceID: &id, Status: c.DefaultQuery("status", models.ReviewApproved)}
+	reviews, err := h.service.ListReviews(c.Request.Context(), filter, opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ListReviews godoc
+// @Summary List reviews across services, e.g. the moderation queue
+// @Tags Reviews
+// @Produce json
+// @Param status query string false "Moderation status"
+// @Param service_id query int false "Filter by service"
+// @Param sort query string false "recent (default) or helpful"
+// @Param order query string false "desc (default) or asc"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} models.Page[models.Review]
+// @Failure 400 {object} ErrorResponse
+// @Router /reviews [get]
+func (h *ReviewHandler) ListReviews(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	filter := models.ReviewFilter{Status: c.Query("status")}
+	if v := c.Query("service_id"); v != "" {
+		serviceID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		filter.ServiceID = &serviceID
+	}
+
+	reviews, err := h.service.ListReviews(c.Request.Context(), filter, opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// ReviewEditRequest is the body used by a customer to edit their review.
+type ReviewEditRequest struct {
+	EditToken string `json:"edit_token" binding:"required"`
+	Rating    int    `json:"rating" binding:"required"`
+	Body      string `json:"body"`
+}
+
+// EditReview godoc
+// @Summary Edit a review within its edit window
+// @Description Needs the edit_token returned when the review was posted. The edited review
+// @Description returns to moderation.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param review body ReviewEditRequest true "New rating and text"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /reviews/{id} [put]
+func (h *ReviewHandler) EditReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ReviewEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	review, err := h.service.EditReview(c.Request.Context(), id, req.EditToken, req.Rating, req.Body)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ReviewModerationRequest is the body used to approve or reject a review.
+type ReviewModerationRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// ModerateReview godoc
+// @Summary Approve or reject a review
+// @Description Needs the admin bearer token.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param moderation body ReviewModerationRequest true "New status"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /reviews/{id}/moderation [put]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ReviewModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	review, err := h.service.ModerateReview(c.Request.Context(), id, req.Status, req.Note)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// HelpfulVoteRequest is the body used to mark a review helpful.
+type HelpfulVoteRequest struct {
+	VoterEmail string `json:"voter_email" binding:"required"`
+}
+
+// MarkReviewHelpful godoc
+// @Summary Mark a review as helpful
+// @Tags Reviews
+// @Accept json
+// @Param id path int true "Review ID"
+// @Param vote body HelpfulVoteRequest true "Voter"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /reviews/{id}/helpful [post]
+func (h *ReviewHandler) MarkReviewHelpful(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req HelpfulVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.MarkHelpful(c.Request.Context(), id, req.VoterEmail); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// draftRequested reports whether a read asks with ?revision=draft for the
+// draft of an entity rather than what is published. Only admins may read
+// drafts; anyone else is answered 403, and an unknown revision 400.
+func draftRequested(c *gin.Context) (draft, ok bool) {
+	switch v := c.Query("revision"); v {
+	case "", models.RevisionPublished:
+		return false, true
+	case models.RevisionDraft:
+		if !isAdmin(c) {
+			c.JSON(http.StatusForbidden, NewErrorResponse(errors.New("only admins may read drafts")))
+			return false, false
+		}
+		// Drafts change without a new version, so they carry no ETag and
+		// must not be cached.
+		c.Header("Cache-Control", "no-store")
+		return true, true
+	default:
+		c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("revision must be %q or %q, not %q",
+			models.RevisionPublished, models.RevisionDraft, v)))
+		return false, false
+	}
+}
+
+// revisionNumber parses the revision number in the path.
+func revisionNumber(c *gin.Context) (int, bool) {
+	n, err := strconv.Atoi(c.Param("number"))
+	if err != nil || n <= 0 {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid revision number %q", c.Param("number"))))
+		return 0, false
+	}
+	return n, true
+}
+
+// diffRange parses the revisions a diff compares from its from and to
+// query parameters, 0 for those left out.
+func diffRange(c *gin.Context) (from, to int, ok bool) {
+	numbers := [2]int{}
+	for i, name := range []string{"from", "to"} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid %s revision %q", name, v)))
+			return 0, 0, false
+		}
+		numbers[i] = n
+	}
+	return numbers[0], numbers[1], true
+}
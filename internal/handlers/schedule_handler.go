@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler struct {
+	service *services.ScheduleService
+}
+
+func NewScheduleHandler(service *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// ListScheduled godoc
+// @Summary List upcoming scheduled publish and unpublish changes
+// @Description Changes already due are listed until the scheduler applies them.
+// @Tags Schedule
+// @Produce json
+// @Param entity_type query string false "category or service"
+// @Param until query string false "RFC 3339 time to list changes up to (default 30 days from now)"
+// @Success 200 {array} models.ScheduledChange
+// @Failure 400 {object} ErrorResponse
+// @Router /schedule [get]
+func (h *ScheduleHandler) ListScheduled(c *gin.Context) {
+	var until time.Time
+	if v := c.Query("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid until %q", v)))
+			return
+		}
+		until = t
+	}
+
+	changes, err := h.service.ListScheduled(c.Request.Context(), c.Query("entity_type"), until)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
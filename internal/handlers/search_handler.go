@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service *services.SearchService
+}
+
+func NewSearchHandler(service *services.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// Search godoc
+// @Summary Full-text search over categories and services
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search terms (web search syntax: quotes, OR, -term)"
+// @Param type query string false "Restrict to category or service results"
+// @Param category_id query int false "Filter by category"
+// @Param is_active query bool false "Filter by active state"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} models.Page[models.SearchResult]
+// @Failure 400 {object} ErrorResponse
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	list, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	opts := models.SearchOptions{
+		Query:    c.Query("q"),
+		Type:     c.Query("type"),
+		IsActive: list.IsActive,
+		Limit:    list.Limit,
+		Cursor:   list.Cursor,
+	}
+	if v := c.Query("category_id"); v != "" {
+		categoryID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+		opts.CategoryID = &categoryID
+	}
+
+	results, err := h.service.Search(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
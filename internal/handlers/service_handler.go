@@ -3,8 +3,10 @@ package handlers
 import (
 	"server/internal/models"
 	"server/internal/services"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,6 +21,8 @@ func NewServiceHandler(service *services.ServiceService) *ServiceHandler {
 
 // CreateService godoc
 // @Summary Create a new service
+// @Description publish_at and unpublish_at schedule when the item goes live or is taken down;
+// @Description they must lie in the future and are cleared once applied.
 // @Tags Services
 // @Accept json
 // @Produce json
@@ -39,43 +43,94 @@ func (h *ServiceHandler) CreateService(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag(service.Version))
 	c.JSON(http.StatusCreated, service)
 }
 
 // GetService godoc
 // @Summary Get service details
+// @Description Serves the published service. Admins may ask for its draft instead.
 // @Tags Services
 // @Produce json
-// @Param id path int true "Service ID"
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param If-None-Match header string false "ETag of a copy already held"
+// @Param id path string true "Service ID or slug"
+// @Param revision query string false "published (default) or draft; draft needs the admin bearer token"
 // @Success 200 {object} models.Service
+// @Success 304
+// @Failure 301 {object} MovedResponse "The slug was retired; Location has the current one"
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /services/{id} [get]
 func (h *ServiceHandler) GetService(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+	draft, ok := draftRequested(c)
+	if !ok {
 		return
 	}
 
-	service, err := h.service.GetService(c.Request.Context(), id)
+	var service *models.Service
+	ref := c.Param("id")
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err == nil {
+		service, err = h.service.GetService(localeContext(c), id)
+	} else {
+		service, err = h.service.GetServiceBySlug(localeContext(c), ref)
+	}
 	if err != nil {
+		if redirectMoved(c, "/services/", err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, NewErrorResponse(err))
 		return
 	}
+	if service != nil && draft {
+		if service, err = h.service.ApplyServiceDraft(c.Request.Context(), service); err != nil {
+			c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, service)
+		return
+	}
+	if service != nil && notModified(c, service.Version) {
+		return
+	}
 
 	c.JSON(http.StatusOK, service)
 }
 
 // ListServices godoc
-// @Summary List all services
+// @Summary List services
 // @Tags Services
 // @Produce json
-// @Success 200 {array} models.Service
+// @Param Accept-Language header string false "Preferred locales for translated content"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param sort query string false "Sort key: sort_order (default, manual order then name), name, id or created_at"
+// @Param order query string false "asc or desc"
+// @Param is_active query bool false "Filter by active state"
+// @Param category_id query int false "Filter by category"
+// @Param lat query number false "Only services offered at this latitude (with lng)"
+// @Param lng query number false "Only services offered at this longitude (with lat)"
+// @Param postal_code query string false "Only services offered in this postal code"
+// @Param tags query string false "Comma-separated tag slugs to filter by"
+// @Param tag_match query string false "any (default) keeps services with any of the tags, all those with every one"
+// @Success 200 {object} models.Page[models.Service]
+// @Failure 400 {object} ErrorResponse
 // @Router /services [get]
 func (h *ServiceHandler) ListServices(c *gin.Context) {
-	services, err := h.service.ListServices(c.Request.Context())
+	opts, err := parseListOptions(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	if err := parseServiceFilters(c, &opts); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	services, err := h.service.ListServices(localeContext(c), opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
 		return
 	}
 
@@ -83,11 +138,17 @@ func (h *ServiceHandler) ListServices(c *gin.Context) {
 }
 
 // ListServicesByCategory godoc
-// @Summary List services by category
+// @Summary List the services and bundles of a category
+// @Description The tag filters narrow the services; bundles are filtered by is_active only.
 // @Tags Services
 // @Produce json
+// @Param Accept-Language header string false "Preferred locales for translated content"
 // @Param categoryId path int true "Category ID"
-// @Success 200 {array} models.Service
+// @Param include_descendants query bool false "Include services of all nested categories"
+// @Param is_active query bool false "Filter by active state"
+// @Param tags query string false "Comma-separated tag slugs to filter by"
+// @Param tag_match query string false "any (default) keeps services with any of the tags, all those with every one"
+// @Success 200 {object} models.CategoryListing
 // @Router /categories/{categoryId}/services [get]
 func (h *ServiceHandler) ListServicesByCategory(c *gin.Context) {
 	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -96,24 +157,81 @@ func (h *ServiceHandler) ListServicesByCategory(c *gin.Context) {
 		return
 	}
 
-	services, err := h.service.ListServicesByCategory(c.Request.Context(), categoryID)
+	includeDescendants := false
+	if v := c.Query("include_descendants"); v != "" {
+		if includeDescendants, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+	}
+	var opts models.ListOptions
+	if v := c.Query("is_active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(fmt.Errorf("invalid is_active %q", v)))
+			return
+		}
+		opts.IsActive = &active
+	}
+	parseTagFilter(c, &opts)
+
+	listing, err := h.service.ListServicesByCategory(localeContext(c), categoryID, includeDescendants, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, NewErrorResponse(err))
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, services)
+	c.JSON(http.StatusOK, listing)
+}
+
+// ReorderServices godoc
+// @Summary Set the display order of a category's services
+// @Description The IDs must list every service of the category not in the trash exactly once.
+// @Tags Services
+// @Accept json
+// @Param categoryId path int true "Category ID"
+// @Param order body ReorderRequest true "Service IDs, first to last"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The IDs do not match the category's current services"
+// @Router /categories/{categoryId}/services/reorder [put]
+func (h *ServiceHandler) ReorderServices(c *gin.Context) {
+	categoryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.ReorderServices(c.Request.Context(), categoryID, req.IDs); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // UpdateService godoc
-// @Summary Update service details
+// @Summary Save service details as its draft
+// @Description The draft replaces any earlier one and goes live once published; until then the
+// @Description service is served as before.
 // @Tags Services
 // @Accept json
 // @Produce json
+// @Param If-Match header string true "ETag of the live version being edited, or *"
 // @Param id path int true "Service ID"
 // @Param service body models.Service true "Service data"
-// @Success 204
+// @Success 200 {object} models.Revision
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
 // @Router /services/{id} [put]
 func (h *ServiceHandler) UpdateService(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -128,21 +246,77 @@ func (h *ServiceHandler) UpdateService(c *gin.Context) {
 		return
 	}
 	req.ID = id
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	req.Version = version
 
-	if err := h.service.UpdateService(c.Request.Context(), &req); err != nil {
+	draft, err := h.service.SaveServiceDraft(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(req.Version))
+	c.JSON(http.StatusOK, draft)
+}
+
+// PatchService godoc
+// @Summary Partially update the draft of a service
+// @Description Takes a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902), chosen by
+// @Description Content-Type, applied to the draft or, without one, the live service. The patched
+// @Description service is validated, saved as the draft and returned.
+// @Tags Services
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param If-Match header string true "ETag of the live version being edited, or *"
+// @Param id path int true "Service ID"
+// @Param patch body object true "Patch document"
+// @Success 200 {object} models.Service
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "A JSON Patch test operation failed"
+// @Failure 412 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /services/{id} [patch]
+func (h *ServiceHandler) PatchService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	p, ok := parsePatch(c)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.PatchServiceDraft(c.Request.Context(), id, version, p)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(service.Version))
+	c.JSON(http.StatusOK, service)
 }
 
 // DeleteService godoc
 // @Summary Delete a service
 // @Tags Services
+// @Param If-Match header string true "ETag of the version being deleted, or *"
 // @Param id path int true "Service ID"
 // @Success 204
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 412 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
 // @Router /services/{id} [delete]
 func (h *ServiceHandler) DeleteService(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -151,10 +325,211 @@ func (h *ServiceHandler) DeleteService(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteService(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteService(c.Request.Context(), id, version); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
 		return
 	}
 
 	c.Status(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// RestoreService godoc
+// @Summary Restore a deleted service from the trash
+// @Description Fails with 409 if the service's category is itself deleted.
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {object} models.Service
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /services/{id}/restore [post]
+func (h *ServiceHandler) RestoreService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	service, err := h.service.RestoreService(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(service.Version))
+	c.JSON(http.StatusOK, service)
+}
+
+// PublishService godoc
+// @Summary Publish the draft of a service
+// @Description Makes the draft live, where customers see it, and its published revision.
+// @Description Only the fields the draft edits are written, so changes made to the service since
+// @Description the draft was saved are kept; when the draft edits one of the same fields the
+// @Description answer is 412. Needs the admin bearer token.
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {object} models.Service
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The service has no draft"
+// @Failure 412 {object} ErrorResponse "The draft and the live service both changed a field"
+// @Router /services/{id}/publish [post]
+func (h *ServiceHandler) PublishService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	service, err := h.service.PublishService(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(service.Version))
+	c.JSON(http.StatusOK, service)
+}
+
+// ListServiceRevisions godoc
+// @Summary List the revisions of a service, newest first
+// @Description Needs the admin bearer token.
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.Revision
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/revisions [get]
+func (h *ServiceHandler) ListServiceRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	revisions, err := h.service.ListServiceRevisions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DiffServiceRevisions godoc
+// @Summary Compare two revisions of a service
+// @Description Needs the admin bearer token.
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param from query int false "Revision number (default the published revision)"
+// @Param to query int false "Revision number (default the draft)"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/revisions/diff [get]
+func (h *ServiceHandler) DiffServiceRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	from, to, ok := diffRange(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.service.DiffServiceRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RollbackService godoc
+// @Summary Roll a service back to an earlier revision
+// @Description Makes the revision live again as a new published revision. The service's
+// @Description schedule and any draft are kept. Needs the admin bearer token.
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param number path int true "Revision number"
+// @Success 200 {object} models.Service
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The revision is the draft"
+// @Router /services/{id}/revisions/{number}/rollback [post]
+func (h *ServiceHandler) RollbackService(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	number, ok := revisionNumber(c)
+	if !ok {
+		return
+	}
+
+	service, err := h.service.RollbackService(c.Request.Context(), id, number)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Header("ETag", etag(service.Version))
+	c.JSON(http.StatusOK, service)
+}
+
+// ListServicePrices godoc
+// @Summary Get a service's price history, or the price in effect at a given time
+// @Tags Services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param at query string false "RFC 3339 timestamp; returns only the price in effect then"
+// @Success 200 {array} models.ServicePrice
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/prices [get]
+func (h *ServiceHandler) ListServicePrices(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if v := c.Query("at"); v != "" {
+		at, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+			return
+		}
+
+		price, err := h.service.GetServicePriceAt(c.Request.Context(), id, at)
+		if err != nil {
+			c.JSON(http.StatusNotFound, NewErrorResponse(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, price)
+		return
+	}
+
+	prices, err := h.service.ListServicePrices(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MovedResponse answers a lookup by a retired slug. Location is where the
+// entity is found now.
+type MovedResponse struct {
+	Error    string `json:"error"`
+	ID       int64  `json:"id"`
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
+
+// redirectMoved answers with a permanent redirect to prefix followed by the
+// current slug when err is a MovedError, and reports whether it did.
+func redirectMoved(c *gin.Context, prefix string, err error) bool {
+	var moved *services.MovedError
+	if !errors.As(err, &moved) {
+		return false
+	}
+
+	location := prefix + moved.Slug
+	if q := c.Request.URL.RawQuery; q != "" {
+		location += "?" + q
+	}
+	c.Header("Location", location)
+	c.JSON(http.StatusMovedPermanently, MovedResponse{
+		Error:    err.Error(),
+		ID:       moved.ID,
+		Slug:     moved.Slug,
+		Location: location,
+	})
+	return true
+}
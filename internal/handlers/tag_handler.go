@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	service *services.TagService
+}
+
+func NewTagHandler(service *services.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// CreateTag godoc
+// @Summary Create a tag
+// @Description The slug, used in ?tags filters, is made from the name unless given.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param tag body models.Tag true "Tag data"
+// @Success 201 {object} models.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req models.Tag
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	tag, err := h.service.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// ListTags godoc
+// @Summary List tags with the number of services carrying each
+// @Tags Tags
+// @Produce json
+// @Param sort query string false "name (default) or usage, most used first"
+// @Success 200 {array} models.Tag
+// @Failure 400 {object} ErrorResponse
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.service.ListTags(c.Request.Context(), c.Query("sort"))
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// UpdateTag godoc
+// @Summary Rename a tag
+// @Description The slug stays as it is unless a new one is given.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body models.Tag true "Tag data"
+// @Success 200 {object} models.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req models.Tag
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+	req.ID = id
+
+	tag, err := h.service.UpdateTag(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag, taking it off every service
+// @Tags Tags
+// @Param id path int true "Tag ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteTag(c.Request.Context(), id); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListServiceTags godoc
+// @Summary List the tags of a service
+// @Tags Tags
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.Tag
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/tags [get]
+func (h *TagHandler) ListServiceTags(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	tags, err := h.service.ListServiceTags(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// AttachTag godoc
+// @Summary Put a tag on a service
+// @Tags Tags
+// @Param id path int true "Service ID"
+// @Param tagId path int true "Tag ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/tags/{tagId} [put]
+func (h *TagHandler) AttachTag(c *gin.Context) {
+	serviceID, tagID, ok := parseServiceTag(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.AttachTag(c.Request.Context(), serviceID, tagID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DetachTag godoc
+// @Summary Take a tag off a service
+// @Tags Tags
+// @Param id path int true "Service ID"
+// @Param tagId path int true "Tag ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/tags/{tagId} [delete]
+func (h *TagHandler) DetachTag(c *gin.Context) {
+	serviceID, tagID, ok := parseServiceTag(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DetachTag(c.Request.Context(), serviceID, tagID); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseServiceTag reads the service and tag IDs of a tag attachment route,
+// answering 400 if either is malformed.
+func parseServiceTag(c *gin.Context) (serviceID, tagID int64, ok bool) {
+	serviceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return 0, 0, false
+	}
+	tagID, err = strconv.ParseInt(c.Param("tagId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return 0, 0, false
+	}
+	return serviceID, tagID, true
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	service *services.TranslationService
+}
+
+func NewTranslationHandler(service *services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{service: service}
+}
+
+// TranslationRequest is the body used to set an entity's content in one
+// locale. Empty fields fall back to other locales.
+type TranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ListCategoryTranslations godoc
+// @Summary List a category's translations
+// @Tags Translations
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Translation
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/translations [get]
+func (h *TranslationHandler) ListCategoryTranslations(c *gin.Context) {
+	h.list(c, models.EntityCategory)
+}
+
+// SetCategoryTranslation godoc
+// @Summary Set a category's name and description in a locale
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param locale path string true "BCP 47 locale, e.g. fr or fr-CA"
+// @Param translation body TranslationRequest true "Translated content"
+// @Success 200 {array} models.Translation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/translations/{locale} [put]
+func (h *TranslationHandler) SetCategoryTranslation(c *gin.Context) {
+	h.set(c, models.EntityCategory)
+}
+
+// DeleteCategoryTranslation godoc
+// @Summary Delete a category's translations in a locale
+// @Tags Translations
+// @Param id path int true "Category ID"
+// @Param locale path string true "BCP 47 locale"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteCategoryTranslation(c *gin.Context) {
+	h.delete(c, models.EntityCategory)
+}
+
+// ListServiceTranslations godoc
+// @Summary List a service's translations
+// @Tags Translations
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.Translation
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/translations [get]
+func (h *TranslationHandler) ListServiceTranslations(c *gin.Context) {
+	h.list(c, models.EntityService)
+}
+
+// SetServiceTranslation godoc
+// @Summary Set a service's name and description in a locale
+// @Tags Translations
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param locale path string true "BCP 47 locale, e.g. fr or fr-CA"
+// @Param translation body TranslationRequest true "Translated content"
+// @Success 200 {array} models.Translation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/translations/{locale} [put]
+func (h *TranslationHandler) SetServiceTranslation(c *gin.Context) {
+	h.set(c, models.EntityService)
+}
+
+// DeleteServiceTranslation godoc
+// @Summary Delete a service's translations in a locale
+// @Tags Translations
+// @Param id path int true "Service ID"
+// @Param locale path string true "BCP 47 locale"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /services/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteServiceTranslation(c *gin.Context) {
+	h.delete(c, models.EntityService)
+}
+
+// MissingTranslations godoc
+// @Summary Report content not yet translated into a locale
+// @Tags Translations
+// @Produce json
+// @Param locale query string true "BCP 47 locale"
+// @Param entity_type query string false "category or service; both by default"
+// @Success 200 {object} models.TranslationReport
+// @Failure 400 {object} ErrorResponse
+// @Router /translations/missing [get]
+func (h *TranslationHandler) MissingTranslations(c *gin.Context) {
+	report, err := h.service.MissingTranslations(c.Request.Context(), c.Query("locale"), c.Query("entity_type"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *TranslationHandler) list(c *gin.Context, entityType string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	translations, err := h.service.ListTranslations(c.Request.Context(), entityType, id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+func (h *TranslationHandler) set(c *gin.Context, entityType string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	fields := map[string]string{
+		models.FieldName:        req.Name,
+		models.FieldDescription: req.Description,
+	}
+	translations, err := h.service.SetTranslations(c.Request.Context(), entityType, id, c.Param("locale"), fields)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+func (h *TranslationHandler) delete(c *gin.Context, entityType string) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	if err := h.service.DeleteTranslations(c.Request.Context(), entityType, id, c.Param("locale")); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), NewErrorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
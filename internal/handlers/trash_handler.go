@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"server/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	service *services.TrashService
+}
+
+func NewTrashHandler(service *services.TrashService) *TrashHandler {
+	return &TrashHandler{service: service}
+}
+
+// ListTrash godoc
+// @Summary List deleted categories and services awaiting restore or purge
+// @Description Items bookings still refer to are never purged; they carry kept_because
+// @Description instead of purge_after.
+// @Tags Trash
+// @Produce json
+// @Param type query string false "category or service; both by default"
+// @Param order query string false "desc (default) or asc"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} models.Page[models.TrashItem]
+// @Failure 400 {object} ErrorResponse
+// @Router /trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	opts, err := parseListOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewErrorResponse(err))
+		return
+	}
+
+	items, err := h.service.ListTrash(c.Request.Context(), c.Query("type"), opts)
+	if err != nil {
+		c.JSON(listErrorStatus(err), NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,76 @@
+// Package i18n negotiates the locale of translated content. Content in
+// DefaultLocale is stored on the entities themselves; other locales are
+// looked up along a fallback chain built from the client's preferences, so
+// a request for fr-CA is served from fr-CA, then fr, then the default.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale of the content stored on entities.
+const DefaultLocale = "en"
+
+// ErrInvalidLocale is returned for locales that are not well-formed BCP 47
+// language tags.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// wildcard is how ParseAcceptLanguage reports "*".
+var wildcard = language.Make("mul")
+
+// Normalize returns the canonical form of a BCP 47 locale such as "fr-CA".
+func Normalize(locale string) (string, error) {
+	tag, err := language.Parse(locale)
+	if err != nil || tag == language.Und {
+		return "", fmt.Errorf("%w %q", ErrInvalidLocale, locale)
+	}
+	return tag.String(), nil
+}
+
+// Chain parses an Accept-Language header into the ordered list of locales to
+// try, each followed by its more general parents. The chain stops at
+// DefaultLocale, whose content needs no lookup, and is empty when the client
+// prefers the default or sent no usable preference.
+func Chain(acceptLanguage string) []string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return nil
+	}
+
+	var chain []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tag == wildcard {
+			continue
+		}
+		for t := tag; t != language.Und; t = t.Parent() {
+			locale := t.String()
+			if locale == DefaultLocale {
+				return chain
+			}
+			if !seen[locale] {
+				seen[locale] = true
+				chain = append(chain, locale)
+			}
+		}
+	}
+	return chain
+}
+
+type localesKey struct{}
+
+// WithLocales returns a context carrying the fallback chain that services
+// should localize content with.
+func WithLocales(ctx context.Context, locales []string) context.Context {
+	return context.WithValue(ctx, localesKey{}, locales)
+}
+
+// Locales returns the fallback chain carried by ctx, if any.
+func Locales(ctx context.Context) []string {
+	locales, _ := ctx.Value(localesKey{}).([]string)
+	return locales
+}
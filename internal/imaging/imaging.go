@@ -0,0 +1,133 @@
+// Package imaging validates uploaded images and produces thumbnails using
+// only the standard library codecs, so JPEG, PNG and GIF are supported.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// MaxPixels bounds the decoded size of an image, guarding against small
+// files that expand to huge bitmaps.
+const MaxPixels = 40_000_000
+
+var (
+	// ErrUnsupportedFormat is returned for content that is not a supported
+	// image format, whatever the client claimed it was.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrTooManyPixels is returned for images larger than MaxPixels.
+	ErrTooManyPixels = errors.New("image dimensions too large")
+)
+
+var decoders = map[string]func(io.Reader) (image.Image, error){
+	"image/jpeg": jpeg.Decode,
+	"image/png":  png.Decode,
+	"image/gif":  gif.Decode,
+}
+
+var configDecoders = map[string]func(io.Reader) (image.Config, error){
+	"image/jpeg": jpeg.DecodeConfig,
+	"image/png":  png.DecodeConfig,
+	"image/gif":  gif.DecodeConfig,
+}
+
+// Sniff returns the content type of data detected from its leading bytes.
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if _, ok := decoders[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, contentType)
+	}
+	return contentType, nil
+}
+
+// Decode sniffs and decodes an image, returning it with its content type.
+// The dimensions are checked before the pixels are decoded.
+func Decode(data []byte) (image.Image, string, error) {
+	contentType, err := Sniff(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cfg, err := configDecoders[contentType](bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", fmt.Errorf("%w: empty image", ErrUnsupportedFormat)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	img, err := decoders[contentType](bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	return img, contentType, nil
+}
+
+// Thumbnail scales src down so neither side exceeds size, keeping its aspect
+// ratio. Each output pixel is the average of the source pixels it covers.
+// Images already small enough are copied unscaled.
+func Thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// ThumbnailType is the content type thumbnails of a sourceType image are
+// encoded as. JPEG sources stay JPEG; others become PNG so transparency
+// survives.
+func ThumbnailType(sourceType string) string {
+	if sourceType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// Encode writes img as contentType, which must be image/jpeg or image/png.
+func Encode(w io.Writer, img image.Image, contentType string) error {
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case "image/png":
+		return png.Encode(w, img)
+	}
+	return fmt.Errorf("%w: cannot encode %s", ErrUnsupportedFormat, contentType)
+}
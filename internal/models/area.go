@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Kinds of service area.
+const (
+	AreaKindPostalCodes = "postal_codes"
+	AreaKindRadius      = "radius"
+)
+
+// ServiceArea is a place where a service is offered. It belongs either to a
+// single service or to a provider, in which case it covers every service the
+// provider performs.
+type ServiceArea struct {
+	ID          int64          `json:"id" db:"area_id"`
+	ServiceID   *int64         `json:"service_id,omitempty" db:"service_id"`
+	ProviderID  *int64         `json:"provider_id,omitempty" db:"provider_id"`
+	Name        string         `json:"name" db:"name"`
+	Kind        string         `json:"kind" db:"kind"`
+	PostalCodes pq.StringArray `json:"postal_codes,omitempty" db:"postal_codes"`
+	CenterLat   *float64       `json:"center_lat,omitempty" db:"center_lat"`
+	CenterLng   *float64       `json:"center_lng,omitempty" db:"center_lng"`
+	RadiusKm    *float64       `json:"radius_km,omitempty" db:"radius_km"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// GeoPoint is a latitude/longitude pair in decimal degrees.
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
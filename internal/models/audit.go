@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+
+	// Publishing a draft or rolling back to an earlier revision, and the
+	// scheduled transitions applied by the scheduler.
+	AuditPublish   = "publish"
+	AuditUnpublish = "unpublish"
+)
+
+// AuditEntry records one mutation of a catalog entity. Before and After are
+// the entity's JSON form around the change (null when it did not exist) and
+// Changes maps each field that moved to its old and new values. Admin is
+// whether the change was made with the admin token; when it was not, Actor
+// is only what the client claimed to be.
+type AuditEntry struct {
+	ID         int64           `json:"id" db:"audit_id"`
+	Actor      string          `json:"actor" db:"actor"`
+	Admin      bool            `json:"admin" db:"admin"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   int64           `json:"entity_id" db:"entity_id"`
+	RequestID  string          `json:"request_id" db:"request_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	Changes    json.RawMessage `json:"changes" db:"changes"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter narrows audit listings. Zero values are not applied.
+type AuditFilter struct {
+	EntityType string
+	EntityID   *int64
+	Actor      string
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimeOfDay is a wall-clock time stored as minutes after midnight and
+// serialized as "HH:MM". "24:00" marks the end of the day.
+type TimeOfDay int
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || len(s) != 5 {
+		return fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	if h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return fmt.Errorf("invalid time of day %q", s)
+	}
+	*t = TimeOfDay(h*60 + m)
+	return nil
+}
+
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return int64(t), nil
+}
+
+func (t *TimeOfDay) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*t = TimeOfDay(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TimeOfDay", src)
+	}
+}
+
+// HoursWindow is a range of local time within one day, [Start, End).
+type HoursWindow struct {
+	Start TimeOfDay `json:"start" db:"start_minute"`
+	End   TimeOfDay `json:"end" db:"end_minute"`
+}
+
+// WorkingHours is a provider's regular window on one weekday (0 = Sunday).
+type WorkingHours struct {
+	Weekday int `json:"weekday" db:"weekday"`
+	HoursWindow
+}
+
+// AvailabilityException replaces a provider's weekly hours on one date. An
+// exception without windows marks the date as a day off.
+type AvailabilityException struct {
+	ProviderID int64         `json:"provider_id"`
+	Date       string        `json:"date"`
+	Windows    []HoursWindow `json:"windows"`
+}
+
+// Blackout is an inclusive range of dates on which a provider is unavailable.
+type Blackout struct {
+	ID         int64  `json:"id" db:"blackout_id"`
+	ProviderID int64  `json:"provider_id" db:"provider_id"`
+	StartDate  string `json:"start_date" db:"start_date"`
+	EndDate    string `json:"end_date" db:"end_date"`
+	Reason     string `json:"reason" db:"reason"`
+}
+
+// TimeSlot is an absolute time range, [Start, End).
+type TimeSlot struct {
+	Start time.Time `json:"start" db:"starts_at"`
+	End   time.Time `json:"end" db:"ends_at"`
+}
+
+// ProviderAvailability lists the open slots of one provider for a service.
+type ProviderAvailability struct {
+	ProviderID   int64      `json:"provider_id"`
+	ProviderName string     `json:"provider_name"`
+	Timezone     string     `json:"timezone"`
+	Slots        []TimeSlot `json:"slots"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Booking statuses. See services.BookingService for the allowed transitions.
+const (
+	BookingRequested  = "requested"
+	BookingConfirmed  = "confirmed"
+	BookingInProgress = "in_progress"
+	BookingCompleted  = "completed"
+	BookingCancelled  = "cancelled"
+)
+
+// Sort keys accepted by the booking list.
+const SortStartsAt = "starts_at"
+
+type Booking struct {
+	ID            int64      `json:"id" db:"booking_id"`
+	ServiceID     int64      `json:"service_id" db:"service_id"`
+	ProviderID    *int64     `json:"provider_id" db:"provider_id"`
+	CustomerName  string     `json:"customer_name" db:"customer_name"`
+	CustomerEmail string     `json:"customer_email" db:"customer_email"`
+	Notes         string     `json:"notes" db:"notes"`
+	StartsAt      time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at" db:"ends_at"`
+	Status        string     `json:"status" db:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+}
+
+// BookingEvent records one status change of a booking. FromStatus is nil for
+// the event created with the booking.
+type BookingEvent struct {
+	ID         int64     `json:"id" db:"event_id"`
+	BookingID  int64     `json:"booking_id" db:"booking_id"`
+	FromStatus *string   `json:"from_status" db:"from_status"`
+	ToStatus   string    `json:"to_status" db:"to_status"`
+	Note       string    `json:"note" db:"note"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// BookingFilter narrows the booking list. Zero values are not applied.
+type BookingFilter struct {
+	ServiceID  *int64
+	ProviderID *int64
+	Status     string
+	From       *time.Time
+	To         *time.Time
+}
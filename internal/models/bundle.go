@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Ways a bundle is priced.
+const (
+	BundlePriceFixed    = "fixed"    // Price is set on the bundle
+	BundlePriceDiscount = "discount" // DiscountBP basis points off ListPrice
+)
+
+// Bundle is a package of services sold together. Prices are in minor units
+// of Currency, the currency of its services. ListPrice is what the services
+// cost separately; Price is what the bundle costs.
+type Bundle struct {
+	ID           int64     `json:"id" db:"bundle_id"`
+	CategoryID   int64     `json:"category_id" db:"category_id"`
+	CategoryName string    `json:"category_name,omitempty" db:"category_name"`
+	Name         string    `json:"name" db:"name"`
+	Description  string    `json:"description" db:"description"`
+	IsActive     bool      `json:"is_active" db:"is_active"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+
+	// InactiveReason says why the bundle was switched off when a service in
+	// it was deactivated or deleted. It clears when the bundle is activated.
+	InactiveReason string `json:"inactive_reason,omitempty" db:"inactive_reason"`
+
+	PriceRule  string `json:"price_rule" db:"price_rule"`
+	Price      int64  `json:"price" db:"price"`
+	DiscountBP int    `json:"discount_bp" db:"discount_bp"`
+	ListPrice  int64  `json:"list_price" db:"-"`
+	Currency   string `json:"currency" db:"-"`
+
+	Items []BundleItem `json:"items" db:"-"`
+}
+
+// CategoryListing is what a category offers: its services and the bundles
+// sold in it.
+type CategoryListing struct {
+	Services []Service `json:"services"`
+	Bundles  []Bundle  `json:"bundles"`
+}
+
+// BundleItem is a service in a bundle, Quantity times. The service fields
+// are read only.
+type BundleItem struct {
+	BundleID    int64  `json:"-" db:"bundle_id"`
+	ServiceID   int64  `json:"service_id" db:"service_id"`
+	Quantity    int    `json:"quantity" db:"quantity"`
+	ServiceName string `json:"service_name" db:"service_name"`
+	UnitPrice   int64  `json:"unit_price" db:"unit_price"`
+	Currency    string `json:"-" db:"currency"`
+
+	// Available is false when the service is inactive or in the trash.
+	Available bool `json:"available" db:"available"`
+}
@@ -1,8 +1,36 @@
 package models
 
+import "time"
+
 type Category struct {
-        ID          int64  `json:"id" db:"category_id"`
-        Name        string `json:"name" db:"name"`
-        Description string `json:"description" db:"description"`
-        IsActive    bool   `json:"is_active" db:"is_active"`
-    }
\ No newline at end of file
+        ID          int64     `json:"id" db:"category_id"`
+        ParentID    *int64    `json:"parent_id" db:"parent_id"`
+        Name        string    `json:"name" db:"name"`
+        Slug        string    `json:"slug" db:"slug"`
+        Description string    `json:"description" db:"description"`
+        IsActive    bool      `json:"is_active" db:"is_active"`
+        PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+        UnpublishAt *time.Time `json:"unpublish_at,omitempty" db:"unpublish_at"`
+        CreatedAt   time.Time `json:"created_at" db:"created_at"`
+        DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+        Version     int64     `json:"version" db:"version"`
+        SortOrder   int       `json:"sort_order" db:"sort_order"`
+        Media       []Media   `json:"media,omitempty" db:"-"`
+    }
+
+
+// CategoryNode is a category together with its nested descendants.
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryDeleteOptions says what becomes of the live subcategories and
+// services of a category being deleted. With neither option set, a category
+// that still has any cannot be deleted.
+type CategoryDeleteOptions struct {
+	// ReassignTo is the category they move to.
+	ReassignTo *int64
+	// Cascade moves them to the trash along with the category.
+	Cascade bool
+}
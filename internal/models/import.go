@@ -0,0 +1,73 @@
+package models
+
+// Formats of catalog imports and exports. XLSX is only exported.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Import modes. An atomic import writes nothing unless every row succeeds; a
+// best-effort import keeps the rows that succeed.
+const (
+	ImportAtomic     = "atomic"
+	ImportBestEffort = "best_effort"
+)
+
+// Outcomes of an imported row.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportOptions controls how a catalog import is run.
+type ImportOptions struct {
+	Format string
+	Mode   string
+	DryRun bool
+}
+
+// ImportRow is one category or service to create, or to update when one
+// with the same name exists. Categories and services are referred to by
+// name. Unset fields keep their current value on update. Exports write rows
+// of the same shape, so they can be imported again.
+type ImportRow struct {
+	Type            string  `json:"type" db:"type"`
+	Name            string  `json:"name" db:"name"`
+	Description     *string `json:"description,omitempty" db:"description"`
+	IsActive        *bool   `json:"is_active,omitempty" db:"is_active"`
+	Parent          string  `json:"parent,omitempty" db:"parent"`
+	Category        string  `json:"category,omitempty" db:"category"`
+	DurationMinutes *int    `json:"duration_minutes,omitempty" db:"duration_minutes"`
+	Price           *int64  `json:"price,omitempty" db:"price"`
+	Currency        string  `json:"currency,omitempty" db:"currency"`
+	PricingUnit     string  `json:"pricing_unit,omitempty" db:"pricing_unit"`
+	MinPrice        *int64  `json:"min_price,omitempty" db:"min_price"`
+	MaxPrice        *int64  `json:"max_price,omitempty" db:"max_price"`
+}
+
+// ImportRowResult reports what happened to one row of an import. ID is left
+// out for failed rows and for created rows that were not kept.
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	ID     *int64 `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportReport is the outcome of an import. Committed is false for dry runs
+// and for atomic imports in which a row failed.
+type ImportReport struct {
+	Mode      string            `json:"mode"`
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Skipped   int               `json:"skipped"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
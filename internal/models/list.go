@@ -0,0 +1,47 @@
+package models
+
+// Sort keys accepted by list endpoints. SortManual is the display order
+// set by reordering, with name breaking ties.
+const (
+	SortManual  = "sort_order"
+	SortName    = "name"
+	SortID      = "id"
+	SortCreated = "created_at"
+)
+
+// Sort directions accepted by list endpoints.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// ListOptions controls keyset pagination, ordering and filtering of list
+// queries. Nil filters are not applied.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+	Sort   string
+	Order  string
+
+	IsActive   *bool
+	CategoryID *int64
+
+	// Near and PostalCode keep only services offered at that location. When
+	// both are set a service matching either is kept.
+	Near       *GeoPoint
+	PostalCode string
+
+	// Tags keeps only services carrying tags with these slugs: any of them,
+	// or all of them when TagMatch is TagMatchAll.
+	Tags     []string
+	TagMatch string
+}
+
+// Page is the envelope returned by paginated list endpoints. NextCursor is
+// opaque to clients and empty on the last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Limit      int    `json:"limit"`
+}
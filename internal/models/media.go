@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Media is an image attached to either a service or a category. The blobs
+// themselves live in storage; URL and ThumbnailURL point at the endpoints
+// serving them.
+type Media struct {
+	ID           int64     `json:"id" db:"media_id"`
+	ServiceID    *int64    `json:"service_id,omitempty" db:"service_id"`
+	CategoryID   *int64    `json:"category_id,omitempty" db:"category_id"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbnailKey string    `json:"-" db:"thumbnail_key"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	SizeBytes    int64     `json:"size_bytes" db:"size_bytes"`
+	Width        int       `json:"width" db:"width"`
+	Height       int       `json:"height" db:"height"`
+	AltText      string    `json:"alt_text" db:"alt_text"`
+	Position     int       `json:"position" db:"position"`
+	IsPrimary    bool      `json:"is_primary" db:"is_primary"`
+	URL          string    `json:"url" db:"-"`
+	ThumbnailURL string    `json:"thumbnail_url" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
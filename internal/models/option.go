@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Kinds of option group, by how a customer answers it.
+const (
+	OptionGroupSingle   = "single"   // one option
+	OptionGroupMulti    = "multi"    // any number of options
+	OptionGroupQuantity = "quantity" // a count of each option, such as rooms
+)
+
+// Ways an option changes the price of a service.
+const (
+	ModifierFixed   = "fixed"   // minor units of the service currency
+	ModifierPercent = "percent" // basis points of the base price
+)
+
+// OptionGroup is a configurable part of a service. A required group must be
+// answered; an optional one may be skipped, but when answered MinSelect and
+// MaxSelect bound the options picked or, for quantity groups, their total
+// count.
+type OptionGroup struct {
+	ID        int64     `json:"id" db:"group_id"`
+	ServiceID int64     `json:"service_id" db:"service_id"`
+	Name      string    `json:"name" db:"name"`
+	Kind      string    `json:"kind" db:"kind"`
+	Required  bool      `json:"required" db:"required"`
+	MinSelect int       `json:"min_select" db:"min_select"`
+	MaxSelect *int      `json:"max_select,omitempty" db:"max_select"`
+	SortOrder int       `json:"sort_order" db:"sort_order"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Options   []Option  `json:"options" db:"-"`
+}
+
+// Option is a choice within an option group. ModifierAmount is added per
+// unit of quantity and may be negative for a discount.
+type Option struct {
+	ID             int64     `json:"id" db:"option_id"`
+	GroupID        int64     `json:"group_id" db:"group_id"`
+	Name           string    `json:"name" db:"name"`
+	ModifierType   string    `json:"modifier_type" db:"modifier_type"`
+	ModifierAmount int64     `json:"modifier_amount" db:"modifier_amount"`
+	SortOrder      int       `json:"sort_order" db:"sort_order"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// QuoteRequest is a configuration of a service to price. Units counts the
+// hours, square metres or items of services not priced flat.
+type QuoteRequest struct {
+	Units      int               `json:"units"`
+	Selections []OptionSelection `json:"selections"`
+}
+
+// OptionSelection picks an option, Quantity times in quantity groups.
+type OptionSelection struct {
+	OptionID int64 `json:"option_id" binding:"required"`
+	Quantity int   `json:"quantity"`
+}
+
+// Quote is the itemized price of a service configuration, in minor units of
+// Currency. Adjustment brings a Subtotal that discounts took below zero back
+// up to it.
+type Quote struct {
+	ServiceID   int64       `json:"service_id"`
+	Currency    string      `json:"currency"`
+	PricingUnit string      `json:"pricing_unit"`
+	Units       int         `json:"units"`
+	BasePrice   int64       `json:"base_price"`
+	Lines       []QuoteLine `json:"lines"`
+	Subtotal    int64       `json:"subtotal"`
+	Adjustment  int64       `json:"adjustment"`
+	Total       int64       `json:"total"`
+}
+
+// QuoteLine is the price of one selected option.
+type QuoteLine struct {
+	GroupID    int64  `json:"group_id"`
+	GroupName  string `json:"group_name"`
+	OptionID   int64  `json:"option_id"`
+	OptionName string `json:"option_name"`
+	Quantity   int    `json:"quantity"`
+	UnitAmount int64  `json:"unit_amount"`
+	Amount     int64  `json:"amount"`
+}
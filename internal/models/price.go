@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Units a service price can be charged per.
+const (
+	PricingUnitFlat       = "flat"
+	PricingUnitPerHour    = "per_hour"
+	PricingUnitPerSqMetre = "per_square_metre"
+	PricingUnitPerItem    = "per_item"
+)
+
+// ServicePrice is one entry of a service's price history. A price applies
+// from EffectiveFrom until the next entry's EffectiveFrom.
+type ServicePrice struct {
+	ID            int64     `json:"id" db:"price_id"`
+	ServiceID     int64     `json:"service_id" db:"service_id"`
+	Price         int64     `json:"price" db:"price"`
+	Currency      string    `json:"currency" db:"currency"`
+	PricingUnit   string    `json:"pricing_unit" db:"pricing_unit"`
+	MinPrice      *int64    `json:"min_price,omitempty" db:"min_price"`
+	MaxPrice      *int64    `json:"max_price,omitempty" db:"max_price"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Provider struct {
+	ID        int64     `json:"id" db:"provider_id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email" db:"email"`
+	Phone     string    `json:"phone" db:"phone"`
+	Timezone  string    `json:"timezone" db:"timezone"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ServiceOffer is a service as performed by a particular provider.
+// EffectivePrice is the provider's override when set, else the service price.
+type ServiceOffer struct {
+	Service
+	PriceOverride  *int64 `json:"price_override" db:"price_override"`
+	EffectivePrice int64  `json:"effective_price" db:"effective_price"`
+}
+
+// ProviderOffer is a provider able to perform a particular service.
+type ProviderOffer struct {
+	Provider
+	PriceOverride  *int64 `json:"price_override" db:"price_override"`
+	EffectivePrice int64  `json:"effective_price" db:"effective_price"`
+}
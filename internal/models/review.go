@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Review moderation statuses. Only approved reviews are public and count
+// towards a service's rating.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Sort keys accepted by review lists.
+const (
+	SortRecent  = "recent"
+	SortHelpful = "helpful"
+)
+
+type Review struct {
+	ID             int64     `json:"id" db:"review_id"`
+	ServiceID      int64     `json:"service_id" db:"service_id"`
+	CustomerEmail  string    `json:"customer_email" db:"customer_email"`
+	CustomerName   string    `json:"customer_name" db:"customer_name"`
+	Rating         int       `json:"rating" db:"rating"`
+	Body           string    `json:"body" db:"body"`
+	Status         string    `json:"status" db:"status"`
+	ModerationNote string    `json:"moderation_note,omitempty" db:"moderation_note"`
+	HelpfulCount   int       `json:"helpful_count" db:"helpful_count"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+
+	// EditToken lets the author edit the review. It is only returned when
+	// the review is posted; just its hash is stored.
+	EditToken     string `json:"edit_token,omitempty" db:"-"`
+	EditTokenHash string `json:"-" db:"edit_token_hash"`
+}
+
+// ReviewFilter narrows review lists. Zero values are not applied.
+type ReviewFilter struct {
+	ServiceID *int64
+	Status    string
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Revision states. An entity has at most one draft and one published
+// revision; the ones published before are archived.
+const (
+	RevisionDraft     = "draft"
+	RevisionPublished = "published"
+	RevisionArchived  = "archived"
+)
+
+// Revision is a saved version of the editable content of a category or
+// service, numbered from 1 per entity. Content holds the writable fields in
+// their JSON form, and BaseContent that of the entity a draft was made from.
+// RestoredFrom is set on revisions made by rolling back to an earlier
+// one.
+type Revision struct {
+	ID           int64            `json:"-" db:"revision_id"`
+	EntityType   string           `json:"entity_type" db:"entity_type"`
+	EntityID     int64            `json:"entity_id" db:"entity_id"`
+	Number       int              `json:"number" db:"number"`
+	Status       string           `json:"status" db:"status"`
+	Content      json.RawMessage  `json:"content" db:"content"`
+	BaseContent  *json.RawMessage `json:"base_content,omitempty" db:"base_content"`
+	RestoredFrom *int             `json:"restored_from,omitempty" db:"restored_from"`
+	CreatedBy    string           `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at" db:"updated_at"`
+	PublishedBy  *string          `json:"published_by,omitempty" db:"published_by"`
+	PublishedAt  *time.Time       `json:"published_at,omitempty" db:"published_at"`
+}
+
+// RevisionDiff maps each field that differs between two revisions of an
+// entity to its values in From and To.
+type RevisionDiff struct {
+	EntityType string          `json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	From       int             `json:"from"`
+	To         int             `json:"to"`
+	Changes    json.RawMessage `json:"changes"`
+}
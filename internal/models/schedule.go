@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ScheduledChange is a pending transition of a category or service, which
+// the scheduler applies at At. IsActive is whether the item is active now.
+type ScheduledChange struct {
+	EntityType string    `json:"entity_type" db:"entity_type"`
+	EntityID   int64     `json:"entity_id" db:"entity_id"`
+	Name       string    `json:"name" db:"name"`
+	Action     string    `json:"action" db:"action"`
+	At         time.Time `json:"at" db:"at"`
+	IsActive   bool      `json:"is_active" db:"is_active"`
+}
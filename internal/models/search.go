@@ -0,0 +1,30 @@
+package models
+
+// Result types returned by search.
+const (
+	SearchTypeCategory = "category"
+	SearchTypeService  = "service"
+)
+
+// SearchOptions describes a full-text search over categories and services.
+type SearchOptions struct {
+	Query      string
+	Type       string
+	CategoryID *int64
+	IsActive   *bool
+	Limit      int
+	Cursor     string
+}
+
+// SearchResult is a single ranked hit. Highlighted fields wrap matched terms
+// in <mark> tags.
+type SearchResult struct {
+	Type            string  `json:"type" db:"type"`
+	ID              int64   `json:"id" db:"id"`
+	CategoryID      *int64  `json:"category_id,omitempty" db:"category_id"`
+	Name            string  `json:"name" db:"name"`
+	IsActive        bool    `json:"is_active" db:"is_active"`
+	NameHighlighted string  `json:"name_highlighted" db:"name_highlighted"`
+	Snippet         string  `json:"snippet" db:"snippet"`
+	Rank            float64 `json:"rank" db:"rank"`
+}
@@ -1,9 +1,44 @@
 package models
 
+import "time"
+
 type Service struct {
-    ID          int64  `json:"id" db:"service_id"`
-    CategoryID  int64  `json:"category_id" db:"category_id"`
-    Name        string `json:"name" db:"name"`
-    Description string `json:"description" db:"description"`
-    IsActive    bool   `json:"is_active" db:"is_active"`
-}
\ No newline at end of file
+    ID           int64     `json:"id" db:"service_id"`
+    CategoryID   int64     `json:"category_id" db:"category_id"`
+    CategoryName string    `json:"category_name,omitempty" db:"category_name"`
+    Name         string    `json:"name" db:"name"`
+    Slug         string    `json:"slug" db:"slug"`
+    Description  string    `json:"description" db:"description"`
+    IsActive     bool      `json:"is_active" db:"is_active"`
+    CreatedAt    time.Time `json:"created_at" db:"created_at"`
+    DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+    // PublishAt and UnpublishAt schedule IsActive to switch on and off.
+    PublishAt   *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+    UnpublishAt *time.Time `json:"unpublish_at,omitempty" db:"unpublish_at"`
+
+    // Version changes with every edit and is served as the ETag.
+    Version int64 `json:"version" db:"version"`
+
+    // SortOrder is the manual display position within the category.
+    SortOrder int `json:"sort_order" db:"sort_order"`
+
+    DurationMinutes int `json:"duration_minutes" db:"duration_minutes"`
+
+    // Prices are in minor units of Currency (e.g. cents).
+    Price       int64  `json:"price" db:"price"`
+    Currency    string `json:"currency" db:"currency"`
+    PricingUnit string `json:"pricing_unit" db:"pricing_unit"`
+    MinPrice    *int64 `json:"min_price,omitempty" db:"min_price"`
+    MaxPrice    *int64 `json:"max_price,omitempty" db:"max_price"`
+
+    // Aggregates of approved reviews, kept up to date by ReviewService.
+    ReviewCount   int     `json:"review_count" db:"review_count"`
+    AverageRating float64 `json:"average_rating" db:"average_rating"`
+
+    // Images, embedded only when a single service is fetched.
+    Media []Media `json:"media,omitempty" db:"-"`
+
+    // Configurable options, embedded only when a single service is fetched.
+    OptionGroups []OptionGroup `json:"option_groups,omitempty" db:"-"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Ways a tag filter combines several tags.
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
+// SortUsage orders tags by how many services carry them, most first.
+const SortUsage = "usage"
+
+// Tag is a label services can carry, finer-grained than their category.
+type Tag struct {
+	ID        int64     `json:"id" db:"tag_id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// ServiceCount is the number of services not in the trash carrying the
+	// tag.
+	ServiceCount int `json:"service_count" db:"service_count"`
+}
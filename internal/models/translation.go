@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Entity types that can be translated.
+const (
+	EntityCategory = "category"
+	EntityService  = "service"
+)
+
+// Translatable fields.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+)
+
+// Translation is the value of one field of an entity in one locale.
+type Translation struct {
+	EntityType string    `json:"entity_type" db:"entity_type"`
+	EntityID   int64     `json:"entity_id" db:"entity_id"`
+	Locale     string    `json:"locale" db:"locale"`
+	Field      string    `json:"field" db:"field"`
+	Value      string    `json:"value" db:"value"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MissingTranslation lists the fields of an entity that have no translation
+// for a locale. Name is the entity's name in the default locale.
+type MissingTranslation struct {
+	EntityType string         `json:"entity_type" db:"entity_type"`
+	EntityID   int64          `json:"entity_id" db:"entity_id"`
+	Name       string         `json:"name" db:"name"`
+	Fields     pq.StringArray `json:"fields" db:"fields"`
+}
+
+// TranslationReport summarises how much of the catalogue is translated into
+// a locale.
+type TranslationReport struct {
+	Locale   string               `json:"locale"`
+	Entities int                  `json:"entities"`
+	Complete int                  `json:"complete"`
+	Missing  []MissingTranslation `json:"missing"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// SortDeletedAt orders trash listings by deletion time.
+const SortDeletedAt = "deleted_at"
+
+// TrashItem is a soft-deleted category or service awaiting restore or purge.
+// An item the purge has to keep says why in KeptBecause and has no
+// PurgeAfter.
+type TrashItem struct {
+	Type        string     `json:"type" db:"type"`
+	ID          int64      `json:"id" db:"id"`
+	Name        string     `json:"name" db:"name"`
+	DeletedAt   time.Time  `json:"deleted_at" db:"deleted_at"`
+	PurgeAfter  *time.Time `json:"purge_after,omitempty" db:"-"`
+	KeptBecause string     `json:"kept_because,omitempty" db:"kept_because"`
+}
+
+// PurgeResult counts the items removed by one purge of the trash.
+type PurgeResult struct {
+	Categories int64 `json:"categories"`
+	Services   int64 `json:"services"`
+}
@@ -0,0 +1,314 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// operation is one step of a JSON Patch. Value is nil when the member is
+// absent, which is distinct from a JSON null.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+
+	path, from pointer
+	value      any
+}
+
+type jsonPatch []operation
+
+func parseJSONPatch(data []byte) (Patch, error) {
+	var ops jsonPatch
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	for i := range ops {
+		op := &ops[i]
+		var err error
+		if op.path, err = parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalid, i, err)
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d: %s requires a value", ErrInvalid, i, op.Op)
+			}
+			if op.value, err = decode(op.Value); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalid, i, err)
+			}
+		case "move", "copy":
+			if op.from, err = parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalid, i, err)
+			}
+			if op.Op == "move" && op.from.isProperPrefixOf(op.path) {
+				return nil, fmt.Errorf("%w: operation %d: cannot move a value into itself", ErrInvalid, i)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown op %q", ErrInvalid, i, op.Op)
+		}
+	}
+	return ops, nil
+}
+
+// Apply runs the operations in order. The patch fails as a whole if any of
+// them fails.
+func (p jsonPatch) Apply(doc []byte) ([]byte, error) {
+	v, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+
+	for i, op := range p {
+		if v, err = op.apply(v); err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(v)
+}
+
+func (op operation) apply(doc any) (any, error) {
+	switch op.Op {
+	case "add":
+		return add(doc, op.path, clone(op.value))
+	case "remove":
+		doc, _, err := remove(doc, op.path)
+		return doc, err
+	case "replace":
+		if len(op.path) == 0 {
+			return clone(op.value), nil
+		}
+		doc, _, err := remove(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, clone(op.value))
+	case "move":
+		doc, value, err := remove(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, value)
+	case "copy":
+		value, err := get(doc, op.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.path, clone(value))
+	case "test":
+		value, err := get(doc, op.path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, op.value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalid, op.Op)
+}
+
+// pointer is a parsed JSON Pointer (RFC 6901). The empty pointer refers to
+// the whole document.
+type pointer []string
+
+func parsePointer(s string) (pointer, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("pointer %q must start with /", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func (p pointer) isProperPrefixOf(q pointer) bool {
+	if len(p) >= len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func get(doc any, path pointer) (any, error) {
+	for _, token := range path {
+		switch c := doc.(type) {
+		case map[string]any:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalid, token)
+			}
+			doc = v
+		case []any:
+			i, err := index(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("%w: cannot descend into a scalar at %q", ErrInvalid, token)
+		}
+	}
+	return doc, nil
+}
+
+// update replaces the container holding the last token of path with the
+// result of fn, returning the updated document.
+func update(doc any, path pointer, fn func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = update(child, path[1:], fn); err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		c[path[0]] = child
+	case []any:
+		i, _ := index(path[0], len(c)-1)
+		c[i] = child
+	}
+	return doc, nil
+}
+
+func add(doc any, path pointer, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			i := len(c)
+			if token != "-" {
+				var err error
+				if i, err = index(token, len(c)); err != nil {
+					return nil, err
+				}
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add %q to a scalar", ErrInvalid, token)
+	})
+}
+
+func remove(doc any, path pointer) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalid)
+	}
+	var removed any
+	doc, err := update(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalid, token)
+			}
+			removed = v
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, err := index(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove %q from a scalar", ErrInvalid, token)
+	})
+	return doc, removed, err
+}
+
+// index parses an array index token, which must be in [0, max].
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || token[0] < '0' || token[0] > '9' || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalid, token)
+	}
+	if i > max {
+		return 0, fmt.Errorf("%w: array index %d is out of bounds", ErrInvalid, i)
+	}
+	return i, nil
+}
+
+func clone(v any) any {
+	switch c := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(c))
+		for k, e := range c {
+			m[k] = clone(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(c))
+		for i, e := range c {
+			s[i] = clone(e)
+		}
+		return s
+	}
+	return v
+}
+
+// equal compares JSON values as RFC 6902 test does: numbers by value and
+// objects regardless of member order.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		m, okX := new(big.Float).SetString(x.String())
+		n, okY := new(big.Float).SetString(y.String())
+		return okX && okY && m.Cmp(n) == 0
+	}
+	return a == b
+}
@@ -0,0 +1,103 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to JSON documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Media types of the supported patch formats.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedType is returned by Parse for media types other than
+	// MergePatchType and JSONPatchType.
+	ErrUnsupportedType = errors.New("unsupported patch type")
+
+	// ErrInvalid is returned for patch documents that are malformed or
+	// refer to locations that do not exist in the patched document.
+	ErrInvalid = errors.New("invalid patch")
+
+	// ErrTestFailed is returned when a JSON Patch test operation does not
+	// hold for the patched document.
+	ErrTestFailed = errors.New("patch test failed")
+)
+
+// Patch is a parsed patch document.
+type Patch interface {
+	// Apply returns the result of patching the JSON document doc.
+	Apply(doc []byte) ([]byte, error)
+}
+
+// Parse parses a patch document of the given media type.
+func Parse(mediaType string, data []byte) (Patch, error) {
+	switch mediaType {
+	case MergePatchType:
+		return parseMergePatch(data)
+	case JSONPatchType:
+		return parseJSONPatch(data)
+	}
+	return nil, fmt.Errorf("%w %q: use %s or %s", ErrUnsupportedType, mediaType, MergePatchType, JSONPatchType)
+}
+
+// decode parses a JSON value, keeping numbers as json.Number so that large
+// integers survive the round trip.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+type mergePatch struct {
+	patch any
+}
+
+func parseMergePatch(data []byte) (Patch, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return mergePatch{patch: v}, nil
+}
+
+func (p mergePatch) Apply(doc []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return json.Marshal(merge(target, p.patch))
+}
+
+// merge is the MergePatch algorithm of RFC 7396: objects are merged member
+// by member, null removes a member, and anything else replaces the target.
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+		} else {
+			t[name] = merge(t[name], value)
+		}
+	}
+	return t
+}
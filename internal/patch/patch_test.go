@@ -0,0 +1,436 @@
+package patch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// jsonEqual reports whether a and b hold the same JSON value, comparing
+// numbers as written.
+func jsonEqual(t *testing.T, a, b string) bool {
+	t.Helper()
+	x, err := decode([]byte(a))
+	if err != nil {
+		t.Fatalf("decode %s: %v", a, err)
+	}
+	y, err := decode([]byte(b))
+	if err != nil {
+		t.Fatalf("decode %s: %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+type patchTest struct {
+	name    string
+	doc     string
+	patch   string
+	want    string
+	wantErr error
+}
+
+func runPatchTests(t *testing.T, mediaType string, tests []patchTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse(mediaType, []byte(tt.patch))
+			if err == nil {
+				var out []byte
+				out, err = p.Apply([]byte(tt.doc))
+				if err == nil && tt.wantErr == nil && !jsonEqual(t, string(out), tt.want) {
+					t.Errorf("got %s, want %s", out, tt.want)
+				}
+			}
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMergePatch runs the examples of RFC 7396, Appendix A.
+func TestMergePatch(t *testing.T) {
+	runPatchTests(t, MergePatchType, []patchTest{
+		{name: "replace member", doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "add member", doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{name: "remove member", doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{name: "remove one of two", doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{name: "array replaces string", doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{name: "string replaces array", doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{
+			name:  "nested objects",
+			doc:   `{"a":{"b":"c"}}`,
+			patch: `{"a":{"b":"d","c":null}}`,
+			want:  `{"a":{"b":"d"}}`,
+		},
+		{name: "arrays are replaced whole", doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{name: "array document", doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{name: "object replaces array", doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{name: "null document", doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{name: "string document", doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{name: "null members kept", doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{name: "object over array", doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{name: "new nested object", doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{name: "large integers survive", doc: `{"a":1}`, patch: `{"b":9007199254740993}`, want: `{"a":1,"b":9007199254740993}`},
+		{name: "malformed patch", doc: `{}`, patch: `{"a":`, wantErr: ErrInvalid},
+		{name: "trailing data", doc: `{}`, patch: `{} {}`, wantErr: ErrInvalid},
+	})
+}
+
+// TestJSONPatch runs the examples of RFC 6902, Appendix A, and the edge
+// cases around them.
+func TestJSONPatch(t *testing.T) {
+	runPatchTests(t, JSONPatchType, []patchTest{
+		// A.1 - A.16
+		{
+			name:  "add an object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "add an array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "remove an object member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "remove an array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:  "replace a value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "move a value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "move an array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name: "test a value: success",
+			doc:  `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},
+				{"op":"test","path":"/foo/1","value":2}]`,
+			want: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:    "test a value: error",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "add a nested member object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			name:  "ignore unrecognized elements",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:    "add to a nonexistent target",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "~ escape ordering",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			name:    "comparing strings and numbers",
+			doc:     `{"/":9,"~1":10}`,
+			patch:   `[{"op":"test","path":"/~01","value":"10"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "add an array value",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+
+		// "-" and array indexes
+		{
+			name:  "add at the end by index",
+			doc:   `{"foo":["a"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"b"}]`,
+			want:  `{"foo":["a","b"]}`,
+		},
+		{
+			name:  "add to an empty array with -",
+			doc:   `{"foo":[]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":1}]`,
+			want:  `{"foo":[1]}`,
+		},
+		{
+			name:    "add past the end",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"add","path":"/foo/2","value":"b"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "remove -",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"remove","path":"/foo/-"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "replace -",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"replace","path":"/foo/-","value":"b"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "test -",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"test","path":"/foo/-","value":"a"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "copy from -",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"copy","from":"/foo/-","path":"/bar"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "move to -",
+			doc:   `{"foo":["a","b","c"]}`,
+			patch: `[{"op":"move","from":"/foo/0","path":"/foo/-"}]`,
+			want:  `{"foo":["b","c","a"]}`,
+		},
+		{
+			name:    "leading zero index",
+			doc:     `{"foo":["a","b"]}`,
+			patch:   `[{"op":"remove","path":"/foo/01"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "negative index",
+			doc:     `{"foo":["a","b"]}`,
+			patch:   `[{"op":"remove","path":"/foo/-1"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "remove past the end",
+			doc:     `{"foo":["a"]}`,
+			patch:   `[{"op":"remove","path":"/foo/1"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "numeric object member",
+			doc:   `{"foo":{"1":"a"}}`,
+			patch: `[{"op":"replace","path":"/foo/1","value":"b"}]`,
+			want:  `{"foo":{"1":"b"}}`,
+		},
+
+		// test
+		{
+			name:  "test numbers by value",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"test","path":"/a","value":1.0}]`,
+			want:  `{"a":1}`,
+		},
+		{
+			name:  "test objects regardless of member order",
+			doc:   `{"a":{"x":1,"y":[true,null]}}`,
+			patch: `[{"op":"test","path":"/a","value":{"y":[true,null],"x":1}}]`,
+			want:  `{"a":{"x":1,"y":[true,null]}}`,
+		},
+		{
+			name:    "test array order matters",
+			doc:     `{"a":[1,2]}`,
+			patch:   `[{"op":"test","path":"/a","value":[2,1]}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "test null",
+			doc:   `{"a":null}`,
+			patch: `[{"op":"test","path":"/a","value":null}]`,
+			want:  `{"a":null}`,
+		},
+		{
+			name:    "test a missing member",
+			doc:     `{}`,
+			patch:   `[{"op":"test","path":"/a","value":null}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "test the whole document",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"test","path":"","value":{"a":1}}]`,
+			want:  `{"a":1}`,
+		},
+		{
+			name: "failed test fails the whole patch",
+			doc:  `{"a":1}`,
+			patch: `[{"op":"replace","path":"/a","value":2},
+				{"op":"test","path":"/a","value":1}]`,
+			wantErr: ErrTestFailed,
+		},
+
+		// move and copy
+		{
+			name:  "move to the same location",
+			doc:   `{"a":{"b":1}}`,
+			patch: `[{"op":"move","from":"/a","path":"/a"}]`,
+			want:  `{"a":{"b":1}}`,
+		},
+		{
+			name:    "move into a child of itself",
+			doc:     `{"a":{"b":1}}`,
+			patch:   `[{"op":"move","from":"/a","path":"/a/b"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "move to a member with a common prefix",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"move","from":"/a","path":"/ab"}]`,
+			want:  `{"ab":1}`,
+		},
+		{
+			name:    "move a missing member",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"move","from":"/b","path":"/c"}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "copy a value",
+			doc:   `{"a":{"b":[1]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"}]`,
+			want:  `{"a":{"b":[1]},"c":{"b":[1]}}`,
+		},
+		{
+			name: "copies are independent",
+			doc:  `{"a":{"b":[1]}}`,
+			patch: `[{"op":"copy","from":"/a","path":"/c"},
+				{"op":"add","path":"/c/b/-","value":2}]`,
+			want: `{"a":{"b":[1]},"c":{"b":[1,2]}}`,
+		},
+		{
+			name:  "copy into an array",
+			doc:   `{"a":["x","y"]}`,
+			patch: `[{"op":"copy","from":"/a/1","path":"/a/0"}]`,
+			want:  `{"a":["y","x","y"]}`,
+		},
+
+		// whole document and other edge cases
+		{
+			name:  "replace the whole document",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"replace","path":"","value":[1,2]}]`,
+			want:  `[1,2]`,
+		},
+		{
+			name:  "add the whole document",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"","value":{"b":2}}]`,
+			want:  `{"b":2}`,
+		},
+		{
+			name:    "remove the whole document",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"remove","path":""}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "add replaces an existing member",
+			doc:   `{"a":1}`,
+			patch: `[{"op":"add","path":"/a","value":2}]`,
+			want:  `{"a":2}`,
+		},
+		{
+			name:  "add null",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/a","value":null}]`,
+			want:  `{"a":null}`,
+		},
+		{
+			name:    "replace a missing member",
+			doc:     `{}`,
+			patch:   `[{"op":"replace","path":"/a","value":1}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:    "descend into a scalar",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"add","path":"/a/b","value":1}]`,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:  "empty member name",
+			doc:   `{"":1}`,
+			patch: `[{"op":"replace","path":"/","value":2}]`,
+			want:  `{"":2}`,
+		},
+		{
+			name:  "no operations",
+			doc:   `{"a":1}`,
+			patch: `[]`,
+			want:  `{"a":1}`,
+		},
+
+		// malformed patches
+		{name: "not an array", doc: `{}`, patch: `{"op":"add"}`, wantErr: ErrInvalid},
+		{name: "unknown op", doc: `{}`, patch: `[{"op":"merge","path":"/a"}]`, wantErr: ErrInvalid},
+		{name: "missing value", doc: `{}`, patch: `[{"op":"add","path":"/a"}]`, wantErr: ErrInvalid},
+		{name: "relative pointer", doc: `{}`, patch: `[{"op":"add","path":"a","value":1}]`, wantErr: ErrInvalid},
+		{
+			name:    "relative from",
+			doc:     `{"a":1}`,
+			patch:   `[{"op":"copy","from":"a","path":"/b"}]`,
+			wantErr: ErrInvalid,
+		},
+	})
+}
+
+func TestParseUnsupportedType(t *testing.T) {
+	for _, mediaType := range []string{"application/json", "text/plain", ""} {
+		if _, err := Parse(mediaType, []byte(`{}`)); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("Parse(%q) = %v, want %v", mediaType, err, ErrUnsupportedType)
+		}
+	}
+}
+
+func TestApplyDoesNotChangePatch(t *testing.T) {
+	p, err := Parse(JSONPatchType, []byte(`[{"op":"add","path":"/a/-","value":{"b":1}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		out, err := p.Apply([]byte(`{"a":[]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, string(out), `{"a":[{"b":1}]}`) {
+			t.Fatalf("got %s", out)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AreaRepo interface {
+	Create(ctx context.Context, area *models.ServiceArea) error
+	GetByID(ctx context.Context, id int64) (*models.ServiceArea, error)
+	GetByService(ctx context.Context, serviceID int64) ([]models.ServiceArea, error)
+	GetByProvider(ctx context.Context, providerID int64) ([]models.ServiceArea, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+const areaColumns = `area_id, service_id, provider_id, name, kind, postal_codes,
+	center_lat, center_lng, radius_km, created_at`
+
+// serviceAreaMatch is the list condition keeping services offered at a
+// location, either through their own areas or those of an active provider
+// performing them. The %s is replaced by the location test against a.
+const serviceAreaMatch = `EXISTS (
+	SELECT 1 FROM service_areas a
+	WHERE (a.service_id = s.service_id OR a.provider_id IN (
+		SELECT ps.provider_id
+		FROM provider_services ps
+		JOIN providers p ON p.provider_id = ps.provider_id
+		WHERE ps.service_id = s.service_id AND p.is_active
+	))
+	AND (%s)
+)`
+
+type areaRepo struct {
+	db *sqlx.DB
+}
+
+func NewAreaRepo(db *sqlx.DB) AreaRepo {
+	return &areaRepo{db: db}
+}
+
+func (r *areaRepo) Create(ctx context.Context, area *models.ServiceArea) error {
+	if area.PostalCodes == nil {
+		area.PostalCodes = []string{}
+	}
+	query := `
+		INSERT INTO service_areas (service_id, provider_id, name, kind, postal_codes,
+		                           center_lat, center_lng, radius_km)
+		VALUES (:service_id, :provider_id, :name, :kind, :postal_codes,
+		        :center_lat, :center_lng, :radius_km)
+		RETURNING area_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), area, query, area)
+}
+
+func (r *areaRepo) GetByID(ctx context.Context, id int64) (*models.ServiceArea, error) {
+	var area models.ServiceArea
+	query := `SELECT ` + areaColumns + ` FROM service_areas WHERE area_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &area, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &area, err
+}
+
+func (r *areaRepo) GetByService(ctx context.Context, serviceID int64) ([]models.ServiceArea, error) {
+	var areas []models.ServiceArea
+	query := `SELECT ` + areaColumns + ` FROM service_areas WHERE service_id = $1 ORDER BY area_id`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &areas, query, serviceID)
+	return areas, err
+}
+
+func (r *areaRepo) GetByProvider(ctx context.Context, providerID int64) ([]models.ServiceArea, error) {
+	var areas []models.ServiceArea
+	query := `SELECT ` + areaColumns + ` FROM service_areas WHERE provider_id = $1 ORDER BY area_id`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &areas, query, providerID)
+	return areas, err
+}
+
+func (r *areaRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM service_areas WHERE area_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditRepo interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	GetAll(ctx context.Context, filter models.AuditFilter, opts models.ListOptions) (*models.Page[models.AuditEntry], error)
+}
+
+// auditColumns reads missing before/after versions as JSON null so they scan
+// into json.RawMessage.
+const auditColumns = `a.audit_id, a.actor, a.admin, a.action, a.entity_type, a.entity_id, a.request_id,
+	COALESCE(a.before, CAST('null' AS JSONB)) AS before,
+	COALESCE(a.after, CAST('null' AS JSONB)) AS after,
+	a.changes, a.created_at`
+
+var auditSortKeys = map[string]sortKey[models.AuditEntry]{
+	models.SortCreated: {
+		columns: []string{"a.created_at", "a.audit_id"},
+		values: func(a *models.AuditEntry) []string {
+			return []string{a.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(a.ID, 10)}
+		},
+	},
+}
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepo(db *sqlx.DB) AuditRepo {
+	return &auditRepo{db: db}
+}
+
+func (r *auditRepo) Create(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor, admin, action, entity_type, entity_id, request_id, before, after, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING audit_id, created_at
+	`
+	return conn(ctx, r.db).QueryRowxContext(ctx, query,
+		entry.Actor, entry.Admin, entry.Action, entry.EntityType, entry.EntityID, entry.RequestID,
+		nullJSON(entry.Before), nullJSON(entry.After), string(entry.Changes),
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func (r *auditRepo) GetAll(ctx context.Context, filter models.AuditFilter, opts models.ListOptions) (*models.Page[models.AuditEntry], error) {
+	key, err := lookupSortKey(auditSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	if filter.EntityType != "" {
+		q.where("a.entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		q.where("a.entity_id = ?", *filter.EntityID)
+	}
+	if filter.Actor != "" {
+		q.where("a.actor = ?", filter.Actor)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s FROM audit_log a%s ORDER BY %s LIMIT %d`,
+		auditColumns, q.clause(), orderBy, opts.Limit+1,
+	))
+	var entries []models.AuditEntry
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &entries, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(entries, key, opts), nil
+}
+
+// nullJSON stores an absent or null JSON document as SQL NULL. Documents are
+// passed as strings, since the driver would send []byte as bytea.
+func nullJSON(doc json.RawMessage) any {
+	if len(doc) == 0 || string(doc) == "null" {
+		return nil
+	}
+	return string(doc)
+}
@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AvailabilityRepo interface {
+	GetWorkingHours(ctx context.Context, providerID int64) ([]models.WorkingHours, error)
+	ReplaceWorkingHours(ctx context.Context, providerID int64, hours []models.WorkingHours) error
+	GetExceptions(ctx context.Context, providerID int64, from, to string) ([]models.AvailabilityException, error)
+	SetException(ctx context.Context, exception *models.AvailabilityException) error
+	DeleteException(ctx context.Context, providerID int64, date string) error
+	GetBlackouts(ctx context.Context, providerID int64, from, to string) ([]models.Blackout, error)
+	CreateBlackout(ctx context.Context, blackout *models.Blackout) error
+	DeleteBlackout(ctx context.Context, providerID, blackoutID int64) error
+	GetBusySlots(ctx context.Context, providerID int64, from, to time.Time) ([]models.TimeSlot, error)
+}
+
+type availabilityRepo struct {
+	db *sqlx.DB
+	tx Transactor
+}
+
+func NewAvailabilityRepo(db *sqlx.DB) AvailabilityRepo {
+	return &availabilityRepo{db: db, tx: NewTransactor(db)}
+}
+
+func (r *availabilityRepo) GetWorkingHours(ctx context.Context, providerID int64) ([]models.WorkingHours, error) {
+	var hours []models.WorkingHours
+	query := `
+		SELECT weekday, start_minute, end_minute
+		FROM provider_working_hours
+		WHERE provider_id = $1
+		ORDER BY weekday, start_minute
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &hours, query, providerID)
+	return hours, err
+}
+
+func (r *availabilityRepo) ReplaceWorkingHours(ctx context.Context, providerID int64, hours []models.WorkingHours) error {
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		q := conn(ctx, r.db)
+		if _, err := q.ExecContext(ctx, `DELETE FROM provider_working_hours WHERE provider_id = $1`, providerID); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO provider_working_hours (provider_id, weekday, start_minute, end_minute)
+			VALUES ($1, $2, $3, $4)
+		`
+		for _, h := range hours {
+			if _, err := q.ExecContext(ctx, query, providerID, h.Weekday, h.Start, h.End); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetExceptions returns the exceptions between two dates (inclusive), one
+// entry per date with its windows grouped together.
+func (r *availabilityRepo) GetExceptions(ctx context.Context, providerID int64, from, to string) ([]models.AvailabilityException, error) {
+	var rows []struct {
+		Date  string `db:"exception_date"`
+		Start *int64 `db:"start_minute"`
+		End   *int64 `db:"end_minute"`
+	}
+	query := `
+		SELECT to_char(exception_date, 'YYYY-MM-DD') AS exception_date, start_minute, end_minute
+		FROM provider_exceptions
+		WHERE provider_id = $1 AND exception_date BETWEEN $2 AND $3
+		ORDER BY exception_date, start_minute
+	`
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &rows, query, providerID, from, to); err != nil {
+		return nil, err
+	}
+
+	exceptions := []models.AvailabilityException{}
+	for _, row := range rows {
+		if n := len(exceptions); n == 0 || exceptions[n-1].Date != row.Date {
+			exceptions = append(exceptions, models.AvailabilityException{
+				ProviderID: providerID,
+				Date:       row.Date,
+				Windows:    []models.HoursWindow{},
+			})
+		}
+		if row.Start != nil && row.End != nil {
+			last := &exceptions[len(exceptions)-1]
+			last.Windows = append(last.Windows, models.HoursWindow{
+				Start: models.TimeOfDay(*row.Start),
+				End:   models.TimeOfDay(*row.End),
+			})
+		}
+	}
+	return exceptions, nil
+}
+
+// SetException replaces any exception already stored for the same date.
+func (r *availabilityRepo) SetException(ctx context.Context, exception *models.AvailabilityException) error {
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.DeleteException(ctx, exception.ProviderID, exception.Date); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		q := conn(ctx, r.db)
+		query := `
+			INSERT INTO provider_exceptions (provider_id, exception_date, start_minute, end_minute)
+			VALUES ($1, $2, $3, $4)
+		`
+		if len(exception.Windows) == 0 {
+			_, err := q.ExecContext(ctx, query, exception.ProviderID, exception.Date, nil, nil)
+			return err
+		}
+		for _, w := range exception.Windows {
+			if _, err := q.ExecContext(ctx, query, exception.ProviderID, exception.Date, w.Start, w.End); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *availabilityRepo) DeleteException(ctx context.Context, providerID int64, date string) error {
+	query := `DELETE FROM provider_exceptions WHERE provider_id = $1 AND exception_date = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, providerID, date)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetBlackouts returns the blackouts overlapping the dates between from and
+// to (inclusive).
+func (r *availabilityRepo) GetBlackouts(ctx context.Context, providerID int64, from, to string) ([]models.Blackout, error) {
+	var blackouts []models.Blackout
+	query := `
+		SELECT blackout_id, provider_id,
+		       to_char(start_date, 'YYYY-MM-DD') AS start_date,
+		       to_char(end_date, 'YYYY-MM-DD') AS end_date,
+		       reason
+		FROM provider_blackouts
+		WHERE provider_id = $1 AND end_date >= $2 AND start_date <= $3
+		ORDER BY start_date
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &blackouts, query, providerID, from, to)
+	return blackouts, err
+}
+
+func (r *availabilityRepo) CreateBlackout(ctx context.Context, blackout *models.Blackout) error {
+	query := `
+		INSERT INTO provider_blackouts (provider_id, start_date, end_date, reason)
+		VALUES (:provider_id, :start_date, :end_date, :reason)
+		RETURNING blackout_id
+	`
+	return namedGet(ctx, conn(ctx, r.db), &blackout.ID, query, blackout)
+}
+
+func (r *availabilityRepo) DeleteBlackout(ctx context.Context, providerID, blackoutID int64) error {
+	query := `DELETE FROM provider_blackouts WHERE provider_id = $1 AND blackout_id = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, providerID, blackoutID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetBusySlots returns the time taken by the provider's open bookings that
+// intersect [from, to).
+func (r *availabilityRepo) GetBusySlots(ctx context.Context, providerID int64, from, to time.Time) ([]models.TimeSlot, error) {
+	var slots []models.TimeSlot
+	query := `
+		SELECT starts_at, ends_at
+		FROM bookings
+		WHERE provider_id = $1
+		  AND status IN ` + bookingActiveStatuses + `
+		  AND starts_at < $3 AND ends_at > $2
+		ORDER BY starts_at
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &slots, query, providerID, from, to)
+	return slots, err
+}
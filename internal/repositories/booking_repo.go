@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type BookingRepo interface {
+	Create(ctx context.Context, booking *models.Booking) error
+	GetByID(ctx context.Context, id int64) (*models.Booking, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Booking, error)
+	GetAll(ctx context.Context, filter models.BookingFilter, opts models.ListOptions) (*models.Page[models.Booking], error)
+	UpdateStatus(ctx context.Context, booking *models.Booking) error
+	AddEvent(ctx context.Context, event *models.BookingEvent) error
+	GetEvents(ctx context.Context, bookingID int64) ([]models.BookingEvent, error)
+	LockService(ctx context.Context, serviceID int64) error
+	LockProvider(ctx context.Context, providerID int64) error
+	HasOverlap(ctx context.Context, providerID int64, start, end time.Time) (bool, error)
+}
+
+const bookingColumns = `b.booking_id, b.service_id, b.provider_id, b.customer_name, b.customer_email,
+	b.notes, b.starts_at, b.ends_at, b.status, b.created_at, b.updated_at,
+	b.confirmed_at, b.started_at, b.completed_at, b.cancelled_at`
+
+// Statuses in which a booking holds its time window.
+const bookingActiveStatuses = `('requested', 'confirmed', 'in_progress')`
+
+var bookingSortKeys = map[string]sortKey[models.Booking]{
+	models.SortStartsAt: {
+		columns: []string{"b.starts_at", "b.booking_id"},
+		values: func(b *models.Booking) []string {
+			return []string{b.StartsAt.Format(time.RFC3339Nano), strconv.FormatInt(b.ID, 10)}
+		},
+	},
+	models.SortID: {
+		columns: []string{"b.booking_id"},
+		values: func(b *models.Booking) []string {
+			return []string{strconv.FormatInt(b.ID, 10)}
+		},
+	},
+	models.SortCreated: {
+		columns: []string{"b.created_at", "b.booking_id"},
+		values: func(b *models.Booking) []string {
+			return []string{b.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(b.ID, 10)}
+		},
+	},
+}
+
+type bookingRepo struct {
+	db *sqlx.DB
+}
+
+func NewBookingRepo(db *sqlx.DB) BookingRepo {
+	return &bookingRepo{db: db}
+}
+
+func (r *bookingRepo) Create(ctx context.Context, booking *models.Booking) error {
+	query := `
+		INSERT INTO bookings (service_id, provider_id, customer_name, customer_email,
+		                      notes, starts_at, ends_at, status)
+		VALUES (:service_id, :provider_id, :customer_name, :customer_email,
+		        :notes, :starts_at, :ends_at, :status)
+		RETURNING booking_id, created_at, updated_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), booking, query, booking)
+}
+
+func (r *bookingRepo) GetByID(ctx context.Context, id int64) (*models.Booking, error) {
+	var booking models.Booking
+	query := `SELECT ` + bookingColumns + ` FROM bookings b WHERE b.booking_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &booking, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &booking, err
+}
+
+// GetByIDForUpdate loads a booking and locks its row until the surrounding
+// transaction ends.
+func (r *bookingRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Booking, error) {
+	var booking models.Booking
+	query := `SELECT ` + bookingColumns + ` FROM bookings b WHERE b.booking_id = $1 FOR UPDATE`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &booking, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &booking, err
+}
+
+func (r *bookingRepo) GetAll(ctx context.Context, filter models.BookingFilter, opts models.ListOptions) (*models.Page[models.Booking], error) {
+	key, err := lookupSortKey(bookingSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	if filter.ServiceID != nil {
+		q.where("b.service_id = ?", *filter.ServiceID)
+	}
+	if filter.ProviderID != nil {
+		q.where("b.provider_id = ?", *filter.ProviderID)
+	}
+	if filter.Status != "" {
+		q.where("b.status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		q.where("b.ends_at > ?", *filter.From)
+	}
+	if filter.To != nil {
+		q.where("b.starts_at < ?", *filter.To)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s FROM bookings b%s ORDER BY %s LIMIT %d`,
+		bookingColumns, q.clause(), orderBy, opts.Limit+1,
+	))
+	var bookings []models.Booking
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &bookings, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(bookings, key, opts), nil
+}
+
+// UpdateStatus stores booking.Status and stamps the timestamp belonging to
+// the new status. The refreshed row is scanned back into booking.
+func (r *bookingRepo) UpdateStatus(ctx context.Context, booking *models.Booking) error {
+	query := `
+		UPDATE bookings b
+		SET status = $2,
+		    updated_at = NOW(),
+		    confirmed_at = CASE WHEN $2 = 'confirmed' THEN NOW() ELSE b.confirmed_at END,
+		    started_at = CASE WHEN $2 = 'in_progress' THEN NOW() ELSE b.started_at END,
+		    completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE b.completed_at END,
+		    cancelled_at = CASE WHEN $2 = 'cancelled' THEN NOW() ELSE b.cancelled_at END
+		WHERE b.booking_id = $1
+		RETURNING ` + bookingColumns
+	return sqlx.GetContext(ctx, conn(ctx, r.db), booking, query, booking.ID, booking.Status)
+}
+
+func (r *bookingRepo) AddEvent(ctx context.Context, event *models.BookingEvent) error {
+	query := `
+		INSERT INTO booking_events (booking_id, from_status, to_status, note)
+		VALUES (:booking_id, :from_status, :to_status, :note)
+		RETURNING event_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), event, query, event)
+}
+
+func (r *bookingRepo) GetEvents(ctx context.Context, bookingID int64) ([]models.BookingEvent, error) {
+	var events []models.BookingEvent
+	query := `
+		SELECT event_id, booking_id, from_status, to_status, note, created_at
+		FROM booking_events
+		WHERE booking_id = $1
+		ORDER BY created_at, event_id
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &events, query, bookingID)
+	return events, err
+}
+
+// LockService keeps a service from being changed until the surrounding
+// transaction ends, while still letting other bookings lock it. A missing
+// service is not an error; callers check for it after locking.
+func (r *bookingRepo) LockService(ctx context.Context, serviceID int64) error {
+	query := `SELECT service_id FROM services WHERE service_id = $1 FOR SHARE`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, serviceID)
+	return err
+}
+
+// LockProvider serializes booking changes for one provider, and keeps it
+// from being changed, until the surrounding transaction ends, so overlap and
+// activity checks cannot race. A missing provider is not an error; callers
+// check for it after locking.
+func (r *bookingRepo) LockProvider(ctx context.Context, providerID int64) error {
+	query := `SELECT provider_id FROM providers WHERE provider_id = $1 FOR UPDATE`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, providerID)
+	return err
+}
+
+// HasOverlap reports whether the provider already holds a booking
+// intersecting [start, end).
+func (r *bookingRepo) HasOverlap(ctx context.Context, providerID int64, start, end time.Time) (bool, error) {
+	var found bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM bookings
+			WHERE provider_id = $1
+			  AND status IN ` + bookingActiveStatuses + `
+			  AND starts_at < $3 AND ends_at > $2
+		)
+	`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &found, query, providerID, start, end)
+	return found, err
+}
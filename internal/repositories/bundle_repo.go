@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type BundleRepo interface {
+	Create(ctx context.Context, bundle *models.Bundle) error
+	GetByID(ctx context.Context, id int64) (*models.Bundle, error)
+	GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Bundle], error)
+	GetByCategory(ctx context.Context, categoryID int64, includeDescendants bool, isActive *bool) ([]models.Bundle, error)
+	Update(ctx context.Context, bundle *models.Bundle) error
+	Delete(ctx context.Context, id int64) error
+	SetItems(ctx context.Context, bundleID int64, items []models.BundleItem) error
+	GetItems(ctx context.Context, bundleIDs []int64) ([]models.BundleItem, error)
+	DeactivateContaining(ctx context.Context, serviceID int64, reason string) error
+	MoveToCategory(ctx context.Context, fromID, toID int64) error
+}
+
+const bundleColumns = `b.bundle_id, b.category_id, c.name AS category_name, b.name, b.description,
+	b.is_active, b.inactive_reason, b.price_rule, b.price, b.discount_bp, b.created_at`
+
+const bundleFrom = `FROM bundles b JOIN categories c ON b.category_id = c.category_id`
+
+var bundleSortKeys = map[string]sortKey[models.Bundle]{
+	models.SortName: {
+		columns: []string{"b.name", "b.bundle_id"},
+		values: func(b *models.Bundle) []string {
+			return []string{b.Name, strconv.FormatInt(b.ID, 10)}
+		},
+	},
+	models.SortID: {
+		columns: []string{"b.bundle_id"},
+		values: func(b *models.Bundle) []string {
+			return []string{strconv.FormatInt(b.ID, 10)}
+		},
+	},
+	models.SortCreated: {
+		columns: []string{"b.created_at", "b.bundle_id"},
+		values: func(b *models.Bundle) []string {
+			return []string{b.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(b.ID, 10)}
+		},
+	},
+}
+
+type bundleRepo struct {
+	db *sqlx.DB
+}
+
+func NewBundleRepo(db *sqlx.DB) BundleRepo {
+	return &bundleRepo{db: db}
+}
+
+// Create adds a bundle without its items, which SetItems saves.
+func (r *bundleRepo) Create(ctx context.Context, bundle *models.Bundle) error {
+	query := `
+		INSERT INTO bundles (category_id, name, description, is_active, inactive_reason, price_rule, price, discount_bp)
+		VALUES (:category_id, :name, :description, :is_active, :inactive_reason, :price_rule, :price, :discount_bp)
+		RETURNING bundle_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), bundle, query, bundle)
+}
+
+// GetByID returns a bundle without its items.
+func (r *bundleRepo) GetByID(ctx context.Context, id int64) (*models.Bundle, error) {
+	var bundle models.Bundle
+	query := `SELECT ` + bundleColumns + ` ` + bundleFrom + ` WHERE b.bundle_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &bundle, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &bundle, err
+}
+
+// GetAll pages through the bundles matching the is_active and category
+// filters of opts, leaving out those of categories in the trash.
+func (r *bundleRepo) GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Bundle], error) {
+	key, err := lookupSortKey(bundleSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	q.where("c.deleted_at IS NULL")
+	if opts.IsActive != nil {
+		q.where("b.is_active = ?", *opts.IsActive)
+	}
+	if opts.CategoryID != nil {
+		q.where("b.category_id = ?", *opts.CategoryID)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s %s%s ORDER BY %s LIMIT %d`,
+		bundleColumns, bundleFrom, q.clause(), orderBy, opts.Limit+1,
+	))
+	var bundles []models.Bundle
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &bundles, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(bundles, key, opts), nil
+}
+
+// GetByCategory returns the bundles of a category, or of it and every
+// category nested below it, by name.
+func (r *bundleRepo) GetByCategory(ctx context.Context, categoryID int64, includeDescendants bool, isActive *bool) ([]models.Bundle, error) {
+	var q listQuery
+	if includeDescendants {
+		q.where("b.category_id IN "+categorySubtree, categoryID)
+		q.where("c.deleted_at IS NULL")
+	} else {
+		q.where("b.category_id = ?", categoryID)
+	}
+	if isActive != nil {
+		q.where("b.is_active = ?", *isActive)
+	}
+
+	query := r.db.Rebind(`SELECT ` + bundleColumns + ` ` + bundleFrom + q.clause() + ` ORDER BY b.name, b.bundle_id`)
+	bundles := []models.Bundle{}
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &bundles, query, q.args...)
+	return bundles, err
+}
+
+// Update saves a bundle without its items. It fails with sql.ErrNoRows when
+// the bundle does not exist.
+func (r *bundleRepo) Update(ctx context.Context, bundle *models.Bundle) error {
+	query := `
+		UPDATE bundles
+		SET category_id = :category_id,
+		    name = :name,
+		    description = :description,
+		    is_active = :is_active,
+		    inactive_reason = :inactive_reason,
+		    price_rule = :price_rule,
+		    price = :price,
+		    discount_bp = :discount_bp
+		WHERE bundle_id = :bundle_id
+	`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, bundle)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *bundleRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM bundles WHERE bundle_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetItems replaces the services of a bundle with items, kept in the order
+// given.
+func (r *bundleRepo) SetItems(ctx context.Context, bundleID int64, items []models.BundleItem) error {
+	db := conn(ctx, r.db)
+	if _, err := db.ExecContext(ctx, `DELETE FROM bundle_items WHERE bundle_id = $1`, bundleID); err != nil {
+		return err
+	}
+
+	serviceIDs := make([]int64, len(items))
+	quantities := make([]int64, len(items))
+	for i, item := range items {
+		serviceIDs[i], quantities[i] = item.ServiceID, int64(item.Quantity)
+	}
+	query := `
+		INSERT INTO bundle_items (bundle_id, service_id, quantity, position)
+		SELECT $1, i.service_id, i.quantity, i.position
+		FROM unnest(CAST($2 AS BIGINT[]), CAST($3 AS INTEGER[])) WITH ORDINALITY AS i(service_id, quantity, position)
+	`
+	_, err := db.ExecContext(ctx, query, bundleID, pq.Array(serviceIDs), pq.Array(quantities))
+	return err
+}
+
+// GetItems returns the items of the given bundles, each bundle's in order,
+// with the current name, price and state of their services.
+func (r *bundleRepo) GetItems(ctx context.Context, bundleIDs []int64) ([]models.BundleItem, error) {
+	query := `
+		SELECT bi.bundle_id, bi.service_id, bi.quantity, s.name AS service_name,
+		       s.price AS unit_price, s.currency, `+serviceActive+` AND s.deleted_at IS NULL AS available
+		FROM bundle_items bi
+		JOIN services s ON s.service_id = bi.service_id
+		WHERE bi.bundle_id = ANY($1)
+		ORDER BY bi.bundle_id, bi.position
+	`
+	items := []models.BundleItem{}
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &items, query, pq.Array(bundleIDs))
+	return items, err
+}
+
+// DeactivateContaining switches off every active bundle containing a
+// service, recording reason.
+func (r *bundleRepo) DeactivateContaining(ctx context.Context, serviceID int64, reason string) error {
+	query := `
+		UPDATE bundles
+		SET is_active = FALSE, inactive_reason = $2
+		WHERE is_active
+		  AND bundle_id IN (SELECT bundle_id FROM bundle_items WHERE service_id = $1)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, serviceID, reason)
+	return err
+}
+
+// MoveToCategory moves every bundle of one category to another.
+func (r *bundleRepo) MoveToCategory(ctx context.Context, fromID, toID int64) error {
+	query := `UPDATE bundles SET category_id = $2 WHERE category_id = $1`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, fromID, toID)
+	return err
+}
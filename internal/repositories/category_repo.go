@@ -5,17 +5,79 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type CategoryRepo interface {
 	Create(ctx context.Context, category *models.Category) error
 	GetByID(ctx context.Context, id int64) (*models.Category, error)
-	GetAll(ctx context.Context) ([]models.Category, error)
+	GetByName(ctx context.Context, name string) (*models.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Category], error)
+	GetRoots(ctx context.Context) ([]models.Category, error)
 	Update(ctx context.Context, category *models.Category) error
-	Delete(ctx context.Context, id int64) error
+	Patch(ctx context.Context, category *models.Category, columns []string) error
+	Delete(ctx context.Context, id, version int64) error
+	GetChildren(ctx context.Context, id int64) ([]models.Category, error)
+	GetAncestors(ctx context.Context, id int64) ([]models.Category, error)
+	GetSubtree(ctx context.Context, id int64) ([]models.Category, error)
+	IsDescendant(ctx context.Context, rootID, id int64) (bool, error)
+	MoveChildren(ctx context.Context, fromID, toID int64) error
+	GetTrashed(ctx context.Context, id int64) (*models.Category, error)
+	Restore(ctx context.Context, id int64) error
+	Reorder(ctx context.Context, ids []int64) error
 }
 
+const categoryColumns = `c.category_id, c.parent_id, c.name, c.slug, c.description, ` + categoryActive + ` AS is_active,
+	c.publish_at, c.unpublish_at, c.created_at, c.deleted_at, c.version, c.sort_order`
+
+// categoryActive is whether a category is active right now, taking the
+// scheduled transitions the scheduler has yet to apply into account.
+const categoryActive = `live_active(c.is_active, c.publish_at, c.unpublish_at)`
+
+// lastSibling is the sort_order that puts a category last among the children
+// of the parent named :parent_id, or among the top-level categories.
+const lastSibling = `(SELECT COALESCE(MAX(sort_order), 0) + 1 FROM categories WHERE parent_id IS NOT DISTINCT FROM :parent_id)`
+
+// categorySortOrder is the sort_order of a category being saved by a named
+// UPDATE: its current one, or last place under its parent when it moves to
+// another.
+const categorySortOrder = `CASE WHEN parent_id IS NOT DISTINCT FROM :parent_id THEN sort_order ELSE ` + lastSibling + ` END`
+
+var categorySortKeys = map[string]sortKey[models.Category]{
+	models.SortManual: {
+		columns: []string{"c.sort_order", "c.name", "c.category_id"},
+		values: func(c *models.Category) []string {
+			return []string{strconv.Itoa(c.SortOrder), c.Name, strconv.FormatInt(c.ID, 10)}
+		},
+	},
+	models.SortName: {
+		columns: []string{"c.name", "c.category_id"},
+		values: func(c *models.Category) []string {
+			return []string{c.Name, strconv.FormatInt(c.ID, 10)}
+		},
+	},
+	models.SortID: {
+		columns: []string{"c.category_id"},
+		values: func(c *models.Category) []string {
+			return []string{strconv.FormatInt(c.ID, 10)}
+		},
+	},
+	models.SortCreated: {
+		columns: []string{"c.created_at", "c.category_id"},
+		values: func(c *models.Category) []string {
+			return []string{c.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(c.ID, 10)}
+		},
+	},
+}
+
+// categoryPatchColumns are the columns Patch may write.
+var categoryPatchColumns = []string{"parent_id", "name", "slug", "description", "is_active", "publish_at", "unpublish_at"}
+
 type categoryRepo struct {
 	db *sqlx.DB
 }
@@ -26,45 +88,124 @@ func NewCategoryRepo(db *sqlx.DB) CategoryRepo {
 
 func (r *categoryRepo) Create(ctx context.Context, category *models.Category) error {
 	query := `
-		INSERT INTO categories (name, description, is_active)
-		VALUES (:name, :description, :is_active)
-		RETURNING category_id
+		INSERT INTO categories (parent_id, name, slug, description, is_active, publish_at, unpublish_at, sort_order)
+		VALUES (:parent_id, :name, :slug, :description, :is_active, :publish_at, :unpublish_at, ` + lastSibling + `)
+		RETURNING category_id, created_at, version, sort_order
 	`
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to prepare query: %w", err)
-	}
-	defer stmt.Close()
-	
-	return stmt.GetContext(ctx, category, category)
+	return namedGet(ctx, conn(ctx, r.db), category, query, category)
 }
 
 func (r *categoryRepo) GetByID(ctx context.Context, id int64) (*models.Category, error) {
 	var category models.Category
-	query := `SELECT * FROM categories WHERE category_id = $1`
-	err := r.db.GetContext(ctx, &category, query, id)
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.category_id = $1 AND c.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &category, err
+}
+
+func (r *categoryRepo) GetByName(ctx context.Context, name string) (*models.Category, error) {
+	var category models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.name = $1 AND c.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &category, err
+}
+
+// GetBySlug returns the live category with the given slug, or nil.
+func (r *categoryRepo) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.slug = $1 AND c.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, slug)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return &category, err
 }
 
-func (r *categoryRepo) GetAll(ctx context.Context) ([]models.Category, error) {
+func (r *categoryRepo) GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Category], error) {
+	key, err := lookupSortKey(categorySortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	q.where("c.deleted_at IS NULL")
+	if opts.IsActive != nil {
+		q.where(categoryActive+" = ?", *opts.IsActive)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s FROM categories c%s ORDER BY %s LIMIT %d`,
+		categoryColumns, q.clause(), orderBy, opts.Limit+1,
+	))
 	var categories []models.Category
-	query := `SELECT * FROM categories ORDER BY name`
-	err := r.db.SelectContext(ctx, &categories, query)
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(categories, key, opts), nil
+}
+
+// GetRoots returns the live top-level categories in display order.
+func (r *categoryRepo) GetRoots(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.parent_id IS NULL AND c.deleted_at IS NULL ORDER BY c.sort_order, c.name`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query)
 	return categories, err
 }
 
+// Update saves a category if it is still at category.Version, which is then
+// set to the new version. A category moved under another parent goes last
+// among its new siblings. It returns sql.ErrNoRows when the category is gone
+// or was changed in the meantime.
 func (r *categoryRepo) Update(ctx context.Context, category *models.Category) error {
 	query := `
 		UPDATE categories 
-		SET name = :name, 
+		SET parent_id = :parent_id,
+		    name = :name,
+		    slug = :slug,
 		    description = :description,
-		    is_active = :is_active
-		WHERE category_id = :category_id
+		    is_active = :is_active,
+		    publish_at = :publish_at,
+		    unpublish_at = :unpublish_at,
+		    sort_order = ` + categorySortOrder + `
+		WHERE category_id = :category_id AND deleted_at IS NULL AND version = :version
+		RETURNING version, sort_order
+	`
+	return namedGet(ctx, conn(ctx, r.db), category, query, category)
+}
+
+// Patch is Update restricted to the given columns, leaving the rest of the
+// row as it is.
+func (r *categoryRepo) Patch(ctx context.Context, category *models.Category, columns []string) error {
+	set, err := setClause(categoryPatchColumns, columns)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(columns, "parent_id") {
+		set += ", sort_order = " + categorySortOrder
+	}
+	query := `
+		UPDATE categories
+		SET ` + set + `
+		WHERE category_id = :category_id AND deleted_at IS NULL AND version = :version
+		RETURNING version, sort_order
 	`
-	result, err := r.db.NamedExecContext(ctx, query, category)
+	return namedGet(ctx, conn(ctx, r.db), category, query, category)
+}
+
+// Delete moves a category to the trash if it is still at the given version.
+// It stays there until restored or purged.
+func (r *categoryRepo) Delete(ctx context.Context, id, version int64) error {
+	query := `UPDATE categories SET deleted_at = NOW() WHERE category_id = $1 AND deleted_at IS NULL AND version = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -76,16 +217,124 @@ func (r *categoryRepo) Update(ctx context.Context, category *models.Category) er
 	return nil
 }
 
-func (r *categoryRepo) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM categories WHERE category_id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+func (r *categoryRepo) GetChildren(ctx context.Context, id int64) ([]models.Category, error) {
+	var categories []models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.parent_id = $1 AND c.deleted_at IS NULL ORDER BY c.sort_order, c.name`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, id)
+	return categories, err
+}
+
+// GetAncestors returns the chain of parents of a category, root first. The
+// category itself is not included.
+func (r *categoryRepo) GetAncestors(ctx context.Context, id int64) ([]models.Category, error) {
+	var categories []models.Category
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id, 0 AS depth FROM categories WHERE category_id = $1
+			UNION ALL
+			SELECT p.parent_id, a.depth + 1
+			FROM categories p
+			JOIN ancestors a ON p.category_id = a.parent_id
+		)
+		SELECT ` + categoryColumns + `
+		FROM categories c
+		JOIN ancestors a ON c.category_id = a.parent_id
+		WHERE c.deleted_at IS NULL
+		ORDER BY a.depth DESC
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, id)
+	return categories, err
+}
+
+// GetSubtree returns a category and all of its descendants, parents always
+// appearing before their children.
+func (r *categoryRepo) GetSubtree(ctx context.Context, id int64) ([]models.Category, error) {
+	var categories []models.Category
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT category_id, 0 AS depth FROM categories WHERE category_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT ch.category_id, st.depth + 1
+			FROM categories ch
+			JOIN subtree st ON ch.parent_id = st.category_id
+			WHERE ch.deleted_at IS NULL
+		)
+		SELECT ` + categoryColumns + `
+		FROM categories c
+		JOIN subtree st ON c.category_id = st.category_id
+		ORDER BY st.depth, c.sort_order, c.name
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &categories, query, id)
+	return categories, err
+}
+
+// IsDescendant reports whether id is rootID or lies anywhere below it.
+func (r *categoryRepo) IsDescendant(ctx context.Context, rootID, id int64) (bool, error) {
+	var found bool
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT category_id FROM categories WHERE category_id = $1
+			UNION
+			SELECT ch.category_id
+			FROM categories ch
+			JOIN subtree st ON ch.parent_id = st.category_id
+		)
+		SELECT EXISTS (SELECT 1 FROM subtree WHERE category_id = $2)
+	`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &found, query, rootID, id)
+	return found, err
+}
+
+// MoveChildren moves the live child categories of one category to the end
+// of those of another, keeping their order.
+func (r *categoryRepo) MoveChildren(ctx context.Context, fromID, toID int64) error {
+	query := `
+		UPDATE categories c
+		SET parent_id = $2,
+		    sort_order = c.sort_order + (SELECT COALESCE(MAX(sort_order), 0) FROM categories WHERE parent_id = $2)
+		WHERE c.parent_id = $1 AND c.deleted_at IS NULL
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, fromID, toID)
+	return err
+}
+
+// GetTrashed returns a category that is in the trash, or nil if there is no
+// such trashed category.
+func (r *categoryRepo) GetTrashed(ctx context.Context, id int64) (*models.Category, error) {
+	var category models.Category
+	query := `SELECT ` + categoryColumns + ` FROM categories c WHERE c.category_id = $1 AND c.deleted_at IS NOT NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &category, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &category, err
+}
+
+// Restore takes a category back out of the trash. It fails with ErrDuplicate
+// when a live category has taken its name in the meantime.
+func (r *categoryRepo) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE categories SET deleted_at = NULL WHERE category_id = $1 AND deleted_at IS NOT NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
-	
+
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Reorder numbers the given categories from 1 in the order listed. Rows
+// already in place are left alone, so their version does not change.
+func (r *categoryRepo) Reorder(ctx context.Context, ids []int64) error {
+	query := `
+		UPDATE categories c
+		SET sort_order = o.position
+		FROM unnest(CAST($1 AS BIGINT[])) WITH ORDINALITY AS o(category_id, position)
+		WHERE c.category_id = o.category_id AND c.sort_order <> o.position
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicate is returned when a write violates a unique constraint.
+var ErrDuplicate = errors.New("duplicate record")
+
+// uniqueViolation is the Postgres SQLSTATE for unique_violation.
+const uniqueViolation = "23505"
+
+// translateError maps driver errors that callers need to tell apart onto the
+// package's sentinel errors, keeping the original message.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", ErrDuplicate, pqErr.Message)
+	}
+	return err
+}
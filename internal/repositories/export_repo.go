@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ExportRepo interface {
+	Export(ctx context.Context, opts models.ListOptions, fn func(row *models.ImportRow) error) error
+}
+
+// exportQuery lists categories, parents before their children, and then
+// services, as rows an import reads back. The %s are the category and service
+// conditions. The ancestors of every row are listed too, whether they match
+// or not, so that an import finds each parent and category it names.
+const exportQuery = `
+	WITH RECURSIVE tree AS (
+		SELECT category_id, 0 AS depth
+		FROM categories
+		WHERE parent_id IS NULL AND deleted_at IS NULL
+		UNION ALL
+		SELECT ch.category_id, t.depth + 1
+		FROM categories ch
+		JOIN tree t ON ch.parent_id = t.category_id
+		WHERE ch.deleted_at IS NULL
+	),
+	matched_categories AS (
+		SELECT c.category_id
+		FROM tree t
+		JOIN categories c ON c.category_id = t.category_id
+		%s
+	),
+	matched_services AS (
+		SELECT s.service_id, s.category_id
+		` + serviceFrom + `
+		%s
+	),
+	exported_categories AS (
+		SELECT category_id
+		FROM (
+			SELECT category_id FROM matched_categories
+			UNION
+			SELECT category_id FROM matched_services
+		) matched
+		UNION
+		SELECT c.parent_id
+		FROM exported_categories e
+		JOIN categories c ON c.category_id = e.category_id
+		WHERE c.parent_id IS NOT NULL
+	)
+	SELECT type, name, description, is_active, parent, category, duration_minutes,
+	       price, currency, pricing_unit, min_price, max_price
+	FROM (
+		SELECT 0 AS kind, t.depth, 'category' AS type, c.name, c.description, ` + categoryActive + ` AS is_active,
+		       COALESCE(p.name, '') AS parent, '' AS category,
+		       CAST(NULL AS INTEGER) AS duration_minutes, CAST(NULL AS BIGINT) AS price,
+		       '' AS currency, '' AS pricing_unit,
+		       CAST(NULL AS BIGINT) AS min_price, CAST(NULL AS BIGINT) AS max_price
+		FROM tree t
+		JOIN categories c ON c.category_id = t.category_id
+		LEFT JOIN categories p ON p.category_id = c.parent_id
+		WHERE t.category_id IN (SELECT category_id FROM exported_categories)
+		UNION ALL
+		SELECT 1, 0, 'service', s.name, s.description, ` + serviceActive + `, '', c.name, s.duration_minutes,
+		       s.price, s.currency, s.pricing_unit, s.min_price, s.max_price
+		` + serviceFrom + `
+		WHERE s.service_id IN (SELECT service_id FROM matched_services)
+	) catalog
+	ORDER BY kind, depth, name
+`
+
+type exportRepo struct {
+	db *sqlx.DB
+}
+
+func NewExportRepo(db *sqlx.DB) ExportRepo {
+	return &exportRepo{db: db}
+}
+
+// Export calls fn with each catalog row in turn as it is read from the
+// database, stopping at the first error. The is_active filter applies to
+// categories and services; the other filters of opts narrow the services.
+// Categories that do not match are still exported when a row below them is.
+func (r *exportRepo) Export(ctx context.Context, opts models.ListOptions, fn func(row *models.ImportRow) error) error {
+	var categories, services listQuery
+	if opts.IsActive != nil {
+		categories.where(categoryActive+" = ?", *opts.IsActive)
+	}
+	filterServices(&services, opts)
+
+	query := r.db.Rebind(fmt.Sprintf(exportQuery, categories.clause(), services.clause()))
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query, append(categories.args, services.args...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.ImportRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"server/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// does not match the requested sort key.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// sortKey describes one way of ordering a list: the SQL columns forming the
+// keyset (always ending with the primary key so it is unique) and how to read
+// the same values back from a row to build the next cursor.
+type sortKey[T any] struct {
+	columns []string
+	values  func(*T) []string
+}
+
+// listQuery accumulates the conditions and arguments of a list query. Conditions
+// use ? placeholders and the final query is rebound for the driver.
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+func (q *listQuery) where(cond string, args ...any) {
+	q.conds = append(q.conds, cond)
+	q.args = append(q.args, args...)
+}
+
+func (q *listQuery) clause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// cursor is the decoded form of an opaque pagination cursor.
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func encodeCursor(sort string, values []string) string {
+	data, _ := json.Marshal(cursor{Sort: sort, Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw, sort string, n int) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sort || len(c.Values) != n {
+		return nil, ErrInvalidCursor
+	}
+	return c.Values, nil
+}
+
+// applyKeyset adds the "after cursor" condition for key to q and returns the
+// matching ORDER BY expression.
+func applyKeyset[T any](q *listQuery, key sortKey[T], opts models.ListOptions) (string, error) {
+	dir, op := "ASC", ">"
+	if opts.Order == models.OrderDesc {
+		dir, op = "DESC", "<"
+	}
+
+	if opts.Cursor != "" {
+		values, err := decodeCursor(opts.Cursor, opts.Sort, len(key.columns))
+		if err != nil {
+			return "", err
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		args := make([]any, len(values))
+		for i, v := range values {
+			args[i] = v
+		}
+		q.where(fmt.Sprintf("(%s) %s (%s)", strings.Join(key.columns, ", "), op, placeholders), args...)
+	}
+
+	order := make([]string, len(key.columns))
+	for i, col := range key.columns {
+		order[i] = col + " " + dir
+	}
+	return strings.Join(order, ", "), nil
+}
+
+// newPage trims the extra look-ahead row fetched by list queries and builds
+// the cursor pointing after the last returned item.
+func newPage[T any](items []T, key sortKey[T], opts models.ListOptions) *models.Page[T] {
+	page := &models.Page[T]{Items: items, Limit: opts.Limit}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(page.Items) > opts.Limit {
+		page.Items = page.Items[:opts.Limit]
+		page.HasMore = true
+		page.NextCursor = encodeCursor(opts.Sort, key.values(&page.Items[len(page.Items)-1]))
+	}
+	return page
+}
+
+func lookupSortKey[T any](keys map[string]sortKey[T], sort string) (sortKey[T], error) {
+	key, ok := keys[sort]
+	if !ok {
+		return key, fmt.Errorf("unsupported sort key %q", sort)
+	}
+	return key, nil
+}
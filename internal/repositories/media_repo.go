@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type MediaRepo interface {
+	Create(ctx context.Context, media *models.Media) error
+	GetByID(ctx context.Context, id int64) (*models.Media, error)
+	GetByService(ctx context.Context, serviceID int64) ([]models.Media, error)
+	GetByCategory(ctx context.Context, categoryID int64) ([]models.Media, error)
+	Update(ctx context.Context, media *models.Media) error
+	ClearPrimary(ctx context.Context, media *models.Media) error
+	Delete(ctx context.Context, id int64) error
+}
+
+const mediaColumns = `media_id, service_id, category_id, storage_key, thumbnail_key,
+	content_type, size_bytes, width, height, alt_text, position, is_primary, created_at`
+
+type mediaRepo struct {
+	db *sqlx.DB
+}
+
+func NewMediaRepo(db *sqlx.DB) MediaRepo {
+	return &mediaRepo{db: db}
+}
+
+func (r *mediaRepo) Create(ctx context.Context, media *models.Media) error {
+	query := `
+		INSERT INTO media (service_id, category_id, storage_key, thumbnail_key, content_type,
+		                   size_bytes, width, height, alt_text, position, is_primary)
+		VALUES (:service_id, :category_id, :storage_key, :thumbnail_key, :content_type,
+		        :size_bytes, :width, :height, :alt_text, :position, :is_primary)
+		RETURNING media_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), media, query, media)
+}
+
+func (r *mediaRepo) GetByID(ctx context.Context, id int64) (*models.Media, error) {
+	var media models.Media
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE media_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &media, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &media, err
+}
+
+func (r *mediaRepo) GetByService(ctx context.Context, serviceID int64) ([]models.Media, error) {
+	var media []models.Media
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE service_id = $1 ORDER BY position, media_id`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &media, query, serviceID)
+	return media, err
+}
+
+func (r *mediaRepo) GetByCategory(ctx context.Context, categoryID int64) ([]models.Media, error) {
+	var media []models.Media
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE category_id = $1 ORDER BY position, media_id`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &media, query, categoryID)
+	return media, err
+}
+
+func (r *mediaRepo) Update(ctx context.Context, media *models.Media) error {
+	query := `
+		UPDATE media
+		SET alt_text = :alt_text,
+		    position = :position,
+		    is_primary = :is_primary
+		WHERE media_id = :media_id
+	`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, media)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ClearPrimary unsets the primary flag on every other item belonging to the
+// same service or category as media.
+func (r *mediaRepo) ClearPrimary(ctx context.Context, media *models.Media) error {
+	query := `
+		UPDATE media SET is_primary = FALSE
+		WHERE is_primary AND media_id <> $3
+		  AND (service_id = $1 OR category_id = $2)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, media.ServiceID, media.CategoryID, media.ID)
+	return err
+}
+
+func (r *mediaRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM media WHERE media_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type OptionRepo interface {
+	CreateGroup(ctx context.Context, group *models.OptionGroup) error
+	GetGroup(ctx context.Context, id int64) (*models.OptionGroup, error)
+	GetByService(ctx context.Context, serviceID int64) ([]models.OptionGroup, error)
+	UpdateGroup(ctx context.Context, group *models.OptionGroup) error
+	DeleteGroup(ctx context.Context, id int64) error
+	CreateOption(ctx context.Context, option *models.Option) error
+	GetOption(ctx context.Context, id int64) (*models.Option, error)
+	GetOptions(ctx context.Context, groupID int64) ([]models.Option, error)
+	UpdateOption(ctx context.Context, option *models.Option) error
+	DeleteOption(ctx context.Context, id int64) error
+}
+
+const optionGroupColumns = `group_id, service_id, name, kind, required, min_select, max_select, sort_order, created_at`
+
+const optionColumns = `option_id, group_id, name, modifier_type, modifier_amount, sort_order, created_at`
+
+type optionRepo struct {
+	db *sqlx.DB
+}
+
+func NewOptionRepo(db *sqlx.DB) OptionRepo {
+	return &optionRepo{db: db}
+}
+
+func (r *optionRepo) CreateGroup(ctx context.Context, group *models.OptionGroup) error {
+	query := `
+		INSERT INTO option_groups (service_id, name, kind, required, min_select, max_select, sort_order)
+		VALUES (:service_id, :name, :kind, :required, :min_select, :max_select, :sort_order)
+		RETURNING group_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), group, query, group)
+}
+
+// GetGroup returns an option group without its options.
+func (r *optionRepo) GetGroup(ctx context.Context, id int64) (*models.OptionGroup, error) {
+	var group models.OptionGroup
+	query := `SELECT ` + optionGroupColumns + ` FROM option_groups WHERE group_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &group, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &group, err
+}
+
+// GetByService returns the option groups of a service with their options,
+// both in display order.
+func (r *optionRepo) GetByService(ctx context.Context, serviceID int64) ([]models.OptionGroup, error) {
+	var groups []models.OptionGroup
+	query := `
+		SELECT ` + optionGroupColumns + ` FROM option_groups
+		WHERE service_id = $1
+		ORDER BY sort_order, group_id
+	`
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &groups, query, serviceID); err != nil {
+		return nil, err
+	}
+
+	var options []models.Option
+	query = `
+		SELECT ` + optionColumns + ` FROM options
+		WHERE group_id IN (SELECT group_id FROM option_groups WHERE service_id = $1)
+		ORDER BY sort_order, option_id
+	`
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &options, query, serviceID); err != nil {
+		return nil, err
+	}
+
+	index := make(map[int64]int, len(groups))
+	for i := range groups {
+		groups[i].Options = []models.Option{}
+		index[groups[i].ID] = i
+	}
+	for _, option := range options {
+		if i, ok := index[option.GroupID]; ok {
+			groups[i].Options = append(groups[i].Options, option)
+		}
+	}
+	return groups, nil
+}
+
+// UpdateGroup saves the settings of an option group. It fails with
+// sql.ErrNoRows when the group does not exist.
+func (r *optionRepo) UpdateGroup(ctx context.Context, group *models.OptionGroup) error {
+	query := `
+		UPDATE option_groups
+		SET name = :name, kind = :kind, required = :required, min_select = :min_select,
+		    max_select = :max_select, sort_order = :sort_order
+		WHERE group_id = :group_id
+	`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, group)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteGroup removes an option group with its options.
+func (r *optionRepo) DeleteGroup(ctx context.Context, id int64) error {
+	query := `DELETE FROM option_groups WHERE group_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *optionRepo) CreateOption(ctx context.Context, option *models.Option) error {
+	query := `
+		INSERT INTO options (group_id, name, modifier_type, modifier_amount, sort_order)
+		VALUES (:group_id, :name, :modifier_type, :modifier_amount, :sort_order)
+		RETURNING option_id, created_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), option, query, option)
+}
+
+func (r *optionRepo) GetOption(ctx context.Context, id int64) (*models.Option, error) {
+	var option models.Option
+	query := `SELECT ` + optionColumns + ` FROM options WHERE option_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &option, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &option, err
+}
+
+func (r *optionRepo) GetOptions(ctx context.Context, groupID int64) ([]models.Option, error) {
+	options := []models.Option{}
+	query := `SELECT ` + optionColumns + ` FROM options WHERE group_id = $1 ORDER BY sort_order, option_id`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &options, query, groupID)
+	return options, err
+}
+
+// UpdateOption saves an option. It fails with sql.ErrNoRows when the option
+// does not exist.
+func (r *optionRepo) UpdateOption(ctx context.Context, option *models.Option) error {
+	query := `
+		UPDATE options
+		SET name = :name, modifier_type = :modifier_type, modifier_amount = :modifier_amount,
+		    sort_order = :sort_order
+		WHERE option_id = :option_id
+	`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, option)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *optionRepo) DeleteOption(ctx context.Context, id int64) error {
+	query := `DELETE FROM options WHERE option_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// setClause builds the SET list of a named UPDATE that writes only columns,
+// each of which must be one of allowed.
+func setClause(allowed, columns []string) (string, error) {
+	if len(columns) == 0 {
+		return "", errors.New("no columns to update")
+	}
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		if !slices.Contains(allowed, column) {
+			return "", fmt.Errorf("column %q cannot be updated", column)
+		}
+		assignments[i] = column + " = :" + column
+	}
+	return strings.Join(assignments, ", "), nil
+}
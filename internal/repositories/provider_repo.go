@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ProviderRepo interface {
+	Create(ctx context.Context, provider *models.Provider) error
+	GetByID(ctx context.Context, id int64) (*models.Provider, error)
+	GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Provider], error)
+	Update(ctx context.Context, provider *models.Provider) error
+	Delete(ctx context.Context, id int64) error
+	AttachService(ctx context.Context, providerID, serviceID int64, priceOverride *int64) error
+	DetachService(ctx context.Context, providerID, serviceID int64) error
+	GetServices(ctx context.Context, providerID int64) ([]models.ServiceOffer, error)
+	GetByService(ctx context.Context, serviceID int64) ([]models.ProviderOffer, error)
+}
+
+const providerColumns = `p.provider_id, p.name, p.email, p.phone, p.timezone, p.is_active, p.created_at`
+
+var providerSortKeys = map[string]sortKey[models.Provider]{
+	models.SortName: {
+		columns: []string{"p.name", "p.provider_id"},
+		values: func(p *models.Provider) []string {
+			return []string{p.Name, strconv.FormatInt(p.ID, 10)}
+		},
+	},
+	models.SortID: {
+		columns: []string{"p.provider_id"},
+		values: func(p *models.Provider) []string {
+			return []string{strconv.FormatInt(p.ID, 10)}
+		},
+	},
+	models.SortCreated: {
+		columns: []string{"p.created_at", "p.provider_id"},
+		values: func(p *models.Provider) []string {
+			return []string{p.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(p.ID, 10)}
+		},
+	},
+}
+
+type providerRepo struct {
+	db *sqlx.DB
+}
+
+func NewProviderRepo(db *sqlx.DB) ProviderRepo {
+	return &providerRepo{db: db}
+}
+
+func (r *providerRepo) Create(ctx context.Context, provider *models.Provider) error {
+	query := `
+		INSERT INTO providers (name, email, phone, timezone, is_active)
+		VALUES (:name, :email, :phone, :timezone, :is_active)
+		RETURNING provider_id, created_at
+	`
+	return translateError(namedGet(ctx, conn(ctx, r.db), provider, query, provider))
+}
+
+func (r *providerRepo) GetByID(ctx context.Context, id int64) (*models.Provider, error) {
+	var provider models.Provider
+	query := `SELECT ` + providerColumns + ` FROM providers p WHERE p.provider_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &provider, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &provider, err
+}
+
+func (r *providerRepo) GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Provider], error) {
+	key, err := lookupSortKey(providerSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	if opts.IsActive != nil {
+		q.where("p.is_active = ?", *opts.IsActive)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s FROM providers p%s ORDER BY %s LIMIT %d`,
+		providerColumns, q.clause(), orderBy, opts.Limit+1,
+	))
+	var providers []models.Provider
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &providers, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(providers, key, opts), nil
+}
+
+func (r *providerRepo) Update(ctx context.Context, provider *models.Provider) error {
+	query := `
+		UPDATE providers
+		SET name = :name,
+		    email = :email,
+		    phone = :phone,
+		    timezone = :timezone,
+		    is_active = :is_active
+		WHERE provider_id = :provider_id
+	`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, provider)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *providerRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM providers WHERE provider_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AttachService links a provider to a service, replacing the price override
+// if the link already exists.
+func (r *providerRepo) AttachService(ctx context.Context, providerID, serviceID int64, priceOverride *int64) error {
+	query := `
+		INSERT INTO provider_services (provider_id, service_id, price_override)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider_id, service_id) DO UPDATE SET price_override = EXCLUDED.price_override
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, providerID, serviceID, priceOverride)
+	return err
+}
+
+func (r *providerRepo) DetachService(ctx context.Context, providerID, serviceID int64) error {
+	query := `DELETE FROM provider_services WHERE provider_id = $1 AND service_id = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, providerID, serviceID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *providerRepo) GetServices(ctx context.Context, providerID int64) ([]models.ServiceOffer, error) {
+	var offers []models.ServiceOffer
+	query := `
+		SELECT ` + serviceColumns + `,
+		       ps.price_override, COALESCE(ps.price_override, s.price) AS effective_price
+		FROM provider_services ps
+		JOIN services s ON s.service_id = ps.service_id
+		JOIN categories c ON s.category_id = c.category_id
+		LEFT JOIN service_ratings sr ON sr.service_id = s.service_id
+		WHERE ps.provider_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.name
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &offers, query, providerID)
+	return offers, err
+}
+
+func (r *providerRepo) GetByService(ctx context.Context, serviceID int64) ([]models.ProviderOffer, error) {
+	var offers []models.ProviderOffer
+	query := `
+		SELECT ` + providerColumns + `,
+		       ps.price_override, COALESCE(ps.price_override, s.price) AS effective_price
+		FROM provider_services ps
+		JOIN providers p ON p.provider_id = ps.provider_id
+		JOIN services s ON s.service_id = ps.service_id
+		WHERE ps.service_id = $1
+		ORDER BY p.name
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &offers, query, serviceID)
+	return offers, err
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ReviewRepo interface {
+	Create(ctx context.Context, review *models.Review) error
+	GetByID(ctx context.Context, id int64) (*models.Review, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Review, error)
+	GetAll(ctx context.Context, filter models.ReviewFilter, opts models.ListOptions) (*models.Page[models.Review], error)
+	Update(ctx context.Context, review *models.Review) error
+	AddHelpfulVote(ctx context.Context, reviewID int64, voterEmail string) (bool, error)
+	AdjustServiceRating(ctx context.Context, serviceID int64, countDelta, totalDelta int) error
+}
+
+const reviewColumns = `r.review_id, r.service_id, r.customer_email, r.customer_name, r.rating,
+	r.body, r.status, r.moderation_note, r.helpful_count, r.created_at, r.updated_at, r.edit_token_hash`
+
+var reviewSortKeys = map[string]sortKey[models.Review]{
+	models.SortRecent: {
+		columns: []string{"r.created_at", "r.review_id"},
+		values: func(r *models.Review) []string {
+			return []string{r.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(r.ID, 10)}
+		},
+	},
+	models.SortHelpful: {
+		columns: []string{"r.helpful_count", "r.created_at", "r.review_id"},
+		values: func(r *models.Review) []string {
+			return []string{
+				strconv.Itoa(r.HelpfulCount),
+				r.CreatedAt.Format(time.RFC3339Nano),
+				strconv.FormatInt(r.ID, 10),
+			}
+		},
+	},
+}
+
+type reviewRepo struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepo(db *sqlx.DB) ReviewRepo {
+	return &reviewRepo{db: db}
+}
+
+func (r *reviewRepo) Create(ctx context.Context, review *models.Review) error {
+	query := `
+		INSERT INTO reviews (service_id, customer_email, customer_name, rating, body, status, edit_token_hash)
+		VALUES (:service_id, :customer_email, :customer_name, :rating, :body, :status, :edit_token_hash)
+		RETURNING review_id, created_at, updated_at
+	`
+	return translateError(namedGet(ctx, conn(ctx, r.db), review, query, review))
+}
+
+func (r *reviewRepo) GetByID(ctx context.Context, id int64) (*models.Review, error) {
+	var review models.Review
+	query := `SELECT ` + reviewColumns + ` FROM reviews r WHERE r.review_id = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &review, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &review, err
+}
+
+// GetByIDForUpdate loads a review and locks its row until the surrounding
+// transaction ends.
+func (r *reviewRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Review, error) {
+	var review models.Review
+	query := `SELECT ` + reviewColumns + ` FROM reviews r WHERE r.review_id = $1 FOR UPDATE`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &review, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &review, err
+}
+
+func (r *reviewRepo) GetAll(ctx context.Context, filter models.ReviewFilter, opts models.ListOptions) (*models.Page[models.Review], error) {
+	key, err := lookupSortKey(reviewSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	if filter.ServiceID != nil {
+		q.where("r.service_id = ?", *filter.ServiceID)
+	}
+	if filter.Status != "" {
+		q.where("r.status = ?", filter.Status)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s FROM reviews r%s ORDER BY %s LIMIT %d`,
+		reviewColumns, q.clause(), orderBy, opts.Limit+1,
+	))
+	var reviews []models.Review
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &reviews, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(reviews, key, opts), nil
+}
+
+func (r *reviewRepo) Update(ctx context.Context, review *models.Review) error {
+	query := `
+		UPDATE reviews
+		SET rating = :rating,
+		    body = :body,
+		    status = :status,
+		    moderation_note = :moderation_note,
+		    updated_at = NOW()
+		WHERE review_id = :review_id
+		RETURNING updated_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), &review.UpdatedAt, query, review)
+}
+
+// AddHelpfulVote records a voter finding a review helpful. It reports false
+// when that voter had already voted for the review.
+func (r *reviewRepo) AddHelpfulVote(ctx context.Context, reviewID int64, voterEmail string) (bool, error) {
+	query := `
+		WITH vote AS (
+			INSERT INTO review_votes (review_id, voter_email)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+			RETURNING review_id
+		)
+		UPDATE reviews SET helpful_count = helpful_count + 1
+		WHERE review_id IN (SELECT review_id FROM vote)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, reviewID, voterEmail)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// AdjustServiceRating applies a change to a service's approved review count
+// and rating total.
+func (r *reviewRepo) AdjustServiceRating(ctx context.Context, serviceID int64, countDelta, totalDelta int) error {
+	query := `
+		INSERT INTO service_ratings (service_id, review_count, rating_total)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (service_id) DO UPDATE
+		SET review_count = service_ratings.review_count + EXCLUDED.review_count,
+		    rating_total = service_ratings.rating_total + EXCLUDED.rating_total
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, serviceID, countDelta, totalDelta)
+	return err
+}
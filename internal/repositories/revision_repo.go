@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RevisionRepo interface {
+	GetByEntity(ctx context.Context, entityType string, entityID int64) ([]models.Revision, error)
+	Get(ctx context.Context, entityType string, entityID int64, number int) (*models.Revision, error)
+	GetByStatus(ctx context.Context, entityType string, entityID int64, status string) (*models.Revision, error)
+	EnsurePublished(ctx context.Context, revision *models.Revision) error
+	SaveDraft(ctx context.Context, revision *models.Revision) error
+	Publish(ctx context.Context, revision *models.Revision, actor string) error
+	CreatePublished(ctx context.Context, revision *models.Revision) error
+}
+
+const revisionColumns = `revision_id, entity_type, entity_id, number, status, content, base_content, restored_from,
+	created_by, created_at, updated_at, published_by, published_at`
+
+// nextRevision numbers a new revision of the entity given by the first two
+// parameters.
+const nextRevision = `(SELECT COALESCE(MAX(number), 0) + 1 FROM revisions WHERE entity_type = $1 AND entity_id = $2)`
+
+type revisionRepo struct {
+	db *sqlx.DB
+}
+
+func NewRevisionRepo(db *sqlx.DB) RevisionRepo {
+	return &revisionRepo{db: db}
+}
+
+// GetByEntity returns the revisions of an entity, newest first.
+func (r *revisionRepo) GetByEntity(ctx context.Context, entityType string, entityID int64) ([]models.Revision, error) {
+	revisions := []models.Revision{}
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM revisions
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY number DESC
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &revisions, query, entityType, entityID)
+	return revisions, err
+}
+
+func (r *revisionRepo) Get(ctx context.Context, entityType string, entityID int64, number int) (*models.Revision, error) {
+	var revision models.Revision
+	query := `SELECT ` + revisionColumns + ` FROM revisions WHERE entity_type = $1 AND entity_id = $2 AND number = $3`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &revision, query, entityType, entityID, number)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &revision, err
+}
+
+// GetByStatus returns the draft or the published revision of an entity.
+func (r *revisionRepo) GetByStatus(ctx context.Context, entityType string, entityID int64, status string) (*models.Revision, error) {
+	var revision models.Revision
+	query := `SELECT ` + revisionColumns + ` FROM revisions WHERE entity_type = $1 AND entity_id = $2 AND status = $3`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &revision, query, entityType, entityID, status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &revision, err
+}
+
+// EnsurePublished records revision as the first, published revision of an
+// entity that has none yet, such as one created before revisions were kept.
+// Otherwise it does nothing.
+func (r *revisionRepo) EnsurePublished(ctx context.Context, revision *models.Revision) error {
+	query := `
+		INSERT INTO revisions (entity_type, entity_id, number, status, content, created_by, published_by, published_at)
+		SELECT CAST($1 AS VARCHAR), CAST($2 AS BIGINT), 1, 'published', CAST($3 AS JSONB),
+		       CAST($4 AS VARCHAR), CAST($4 AS VARCHAR), NOW()
+		WHERE NOT EXISTS (SELECT 1 FROM revisions WHERE entity_type = $1 AND entity_id = $2)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		revision.EntityType, revision.EntityID, string(revision.Content), revision.CreatedBy)
+	return err
+}
+
+// SaveDraft stores revision as the draft of its entity, replacing the
+// content and base content of the draft it already has, and fills in the
+// rest of revision.
+func (r *revisionRepo) SaveDraft(ctx context.Context, revision *models.Revision) error {
+	query := `
+		INSERT INTO revisions (entity_type, entity_id, number, status, content, base_content, created_by)
+		VALUES ($1, $2, ` + nextRevision + `, 'draft', $3, $4, $5)
+		ON CONFLICT (entity_type, entity_id) WHERE status = 'draft'
+		DO UPDATE SET content = EXCLUDED.content, base_content = EXCLUDED.base_content, updated_at = NOW()
+		RETURNING ` + revisionColumns
+	var base any
+	if revision.BaseContent != nil {
+		base = string(*revision.BaseContent)
+	}
+	return sqlx.GetContext(ctx, conn(ctx, r.db), revision, query,
+		revision.EntityType, revision.EntityID, string(revision.Content), base, revision.CreatedBy)
+}
+
+// Publish makes a draft the published revision of its entity, archiving the
+// one published before.
+func (r *revisionRepo) Publish(ctx context.Context, revision *models.Revision, actor string) error {
+	if err := r.archivePublished(ctx, revision.EntityType, revision.EntityID); err != nil {
+		return err
+	}
+	query := `
+		UPDATE revisions
+		SET status = 'published', published_by = $2, published_at = NOW()
+		WHERE revision_id = $1 AND status = 'draft'
+		RETURNING ` + revisionColumns
+	return sqlx.GetContext(ctx, conn(ctx, r.db), revision, query, revision.ID, actor)
+}
+
+// CreatePublished records revision as a new revision of its entity,
+// published straight away, archiving the one published before.
+func (r *revisionRepo) CreatePublished(ctx context.Context, revision *models.Revision) error {
+	if err := r.archivePublished(ctx, revision.EntityType, revision.EntityID); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO revisions (entity_type, entity_id, number, status, content, restored_from,
+		                       created_by, published_by, published_at)
+		VALUES ($1, $2, ` + nextRevision + `, 'published', $3, $4, $5, $5, NOW())
+		RETURNING ` + revisionColumns
+	return sqlx.GetContext(ctx, conn(ctx, r.db), revision, query,
+		revision.EntityType, revision.EntityID, string(revision.Content), revision.RestoredFrom, revision.CreatedBy)
+}
+
+// archivePublished archives the published revision of an entity. Only one
+// revision may be published at a time, so this must run first.
+func (r *revisionRepo) archivePublished(ctx context.Context, entityType string, entityID int64) error {
+	query := `UPDATE revisions SET status = 'archived' WHERE entity_type = $1 AND entity_id = $2 AND status = 'published'`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, entityType, entityID)
+	return err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ScheduleRepo interface {
+	GetUpcoming(ctx context.Context, entityType string, until time.Time) ([]models.ScheduledChange, error)
+	Apply(ctx context.Context, entityType string, id int64) (wasActive, isActive, applied bool, err error)
+}
+
+// scheduleColumns maps the scheduled actions to the column holding their
+// time.
+var scheduleColumns = [][2]string{
+	{models.AuditPublish, "publish_at"},
+	{models.AuditUnpublish, "unpublish_at"},
+}
+
+type scheduleRepo struct {
+	db *sqlx.DB
+}
+
+func NewScheduleRepo(db *sqlx.DB) ScheduleRepo {
+	return &scheduleRepo{db: db}
+}
+
+// GetUpcoming returns the pending transitions of live categories and
+// services due by until, or only of those of entityType when it is set,
+// soonest first. Transitions already due are included until applied.
+func (r *scheduleRepo) GetUpcoming(ctx context.Context, entityType string, until time.Time) ([]models.ScheduledChange, error) {
+	var branches []string
+	var args []any
+	for _, t := range []string{models.EntityCategory, models.EntityService} {
+		if entityType != "" && entityType != t {
+			continue
+		}
+		table := entityTables[t]
+		for _, c := range scheduleColumns {
+			branches = append(branches, fmt.Sprintf(`
+				SELECT CAST(? AS VARCHAR) AS entity_type, e.%[2]s AS entity_id, e.name,
+				       CAST(? AS VARCHAR) AS action, e.%[3]s AS at,
+				       live_active(e.is_active, e.publish_at, e.unpublish_at) AS is_active
+				FROM %[1]s e
+				WHERE e.deleted_at IS NULL AND e.%[3]s <= ?`, table[0], table[1], c[1]))
+			args = append(args, t, c[0], until)
+		}
+	}
+	if len(branches) == 0 {
+		return nil, fmt.Errorf("unsupported entity type %q", entityType)
+	}
+
+	query := r.db.Rebind(strings.Join(branches, "\n\t\t\tUNION ALL") + `
+		ORDER BY at, entity_type, entity_id, action`)
+	changes := []models.ScheduledChange{}
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &changes, query, args...)
+	return changes, err
+}
+
+// Apply carries out the transitions of an entity that have come due,
+// setting its active flag and clearing their times. It reports the flag
+// before and after, and whether anything was due at all.
+func (r *scheduleRepo) Apply(ctx context.Context, entityType string, id int64) (wasActive, isActive, applied bool, err error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return false, false, false, fmt.Errorf("unsupported entity type %q", entityType)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %[1]s e
+		SET is_active = live_active(e.is_active, e.publish_at, e.unpublish_at),
+		    publish_at = CASE WHEN e.publish_at <= NOW() THEN NULL ELSE e.publish_at END,
+		    unpublish_at = CASE WHEN e.unpublish_at <= NOW() THEN NULL ELSE e.unpublish_at END
+		FROM (SELECT %[2]s, is_active FROM %[1]s WHERE %[2]s = $1 FOR UPDATE) old
+		WHERE e.%[2]s = old.%[2]s
+		  AND e.deleted_at IS NULL
+		  AND (e.publish_at <= NOW() OR e.unpublish_at <= NOW())
+		RETURNING old.is_active, e.is_active
+	`, table[0], table[1])
+	err = conn(ctx, r.db).QueryRowxContext(ctx, query, id).Scan(&wasActive, &isActive)
+	if err == sql.ErrNoRows {
+		return false, false, false, nil
+	}
+	if err != nil {
+		return false, false, false, err
+	}
+	return wasActive, isActive, true, nil
+}
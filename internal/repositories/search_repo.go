@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// searchCursorSort names the order of search results in their cursors: by
+// rank, best first, then type and ID.
+const searchCursorSort = "rank"
+
+type SearchRepo interface {
+	Search(ctx context.Context, opts models.SearchOptions) (*models.Page[models.SearchResult], error)
+}
+
+type searchRepo struct {
+	db *sqlx.DB
+}
+
+func NewSearchRepo(db *sqlx.DB) SearchRepo {
+	return &searchRepo{db: db}
+}
+
+func (r *searchRepo) Search(ctx context.Context, opts models.SearchOptions) (*models.Page[models.SearchResult], error) {
+	// The cursor holds the rank, type and ID of the last hit returned; the
+	// next page starts after it.
+	var after listQuery
+	if opts.Cursor != "" {
+		values, err := decodeCursor(opts.Cursor, searchCursorSort, 3)
+		if err != nil {
+			return nil, err
+		}
+		rank, err := strconv.ParseFloat(values[0], 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		id, err := strconv.ParseInt(values[2], 10, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after.where("(rank < CAST(? AS REAL) OR rank = CAST(? AS REAL) AND (type, id) > (?, ?))",
+			rank, rank, values[1], id)
+	}
+
+	args := []any{opts.Query}
+	var branches []string
+
+	if opts.Type == "" || opts.Type == models.SearchTypeCategory {
+		q := listQuery{}
+		q.where("c.search_vector @@ q.query")
+		q.where("c.deleted_at IS NULL")
+		if opts.IsActive != nil {
+			q.where(categoryActive+" = ?", *opts.IsActive)
+		}
+		if opts.CategoryID != nil {
+			q.where("c.category_id = ?", *opts.CategoryID)
+		}
+		branches = append(branches, `
+			SELECT 'category' AS type, c.category_id AS id, NULL::BIGINT AS category_id,
+			       c.name, c.description, `+categoryActive+` AS is_active,
+			       ts_rank(c.search_vector, q.query) AS rank
+			FROM categories c, q`+q.clause())
+		args = append(args, q.args...)
+	}
+
+	if opts.Type == "" || opts.Type == models.SearchTypeService {
+		q := listQuery{}
+		q.where("s.search_vector @@ q.query")
+		q.where("s.deleted_at IS NULL")
+		if opts.IsActive != nil {
+			q.where(serviceActive+" = ?", *opts.IsActive)
+		}
+		if opts.CategoryID != nil {
+			q.where("s.category_id = ?", *opts.CategoryID)
+		}
+		branches = append(branches, `
+			SELECT 'service' AS type, s.service_id AS id, s.category_id,
+			       s.name, s.description, `+serviceActive+` AS is_active,
+			       ts_rank(s.search_vector, q.query) AS rank
+			FROM services s, q`+q.clause())
+		args = append(args, q.args...)
+	}
+
+	// Headlines are expensive, so they are only computed for the page of
+	// hits that survives ranking and the limit.
+	query := r.db.Rebind(fmt.Sprintf(`
+		WITH q AS (SELECT websearch_to_tsquery('english', ?) AS query),
+		hits AS (
+			SELECT * FROM (%s
+			) ranked%s
+			ORDER BY rank DESC, type, id
+			LIMIT %d
+		)
+		SELECT hits.type, hits.id, hits.category_id, hits.name, hits.is_active, hits.rank,
+		       ts_headline('english', hits.name, q.query,
+		           'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlighted,
+		       ts_headline('english', hits.description, q.query,
+		           'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS snippet
+		FROM hits, q
+		ORDER BY hits.rank DESC, hits.type, hits.id
+	`, strings.Join(branches, "\n\t\t\tUNION ALL\n"), after.clause(), opts.Limit+1))
+	args = append(args, after.args...)
+
+	var results []models.SearchResult
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &results, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &models.Page[models.SearchResult]{Items: results, Limit: opts.Limit}
+	if page.Items == nil {
+		page.Items = []models.SearchResult{}
+	}
+	if len(page.Items) > opts.Limit {
+		page.Items = page.Items[:opts.Limit]
+		page.HasMore = true
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(searchCursorSort, []string{
+			strconv.FormatFloat(last.Rank, 'g', -1, 64), last.Type, strconv.FormatInt(last.ID, 10),
+		})
+	}
+	return page, nil
+}
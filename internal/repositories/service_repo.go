@@ -5,89 +5,291 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ServiceRepo interface {
 	Create(ctx context.Context, service *models.Service) error
 	GetByID(ctx context.Context, id int64) (*models.Service, error)
-	GetByCategory(ctx context.Context, categoryID int64) ([]models.Service, error)
-	GetAll(ctx context.Context) ([]models.Service, error)
+	GetByName(ctx context.Context, name string) (*models.Service, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Service, error)
+	GetByCategory(ctx context.Context, categoryID int64, includeDescendants bool, opts models.ListOptions) ([]models.Service, error)
+	GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Service], error)
 	Update(ctx context.Context, service *models.Service) error
-	Delete(ctx context.Context, id int64) error
+	Patch(ctx context.Context, service *models.Service, columns []string) error
+	Delete(ctx context.Context, id, version int64) error
+	GetPriceHistory(ctx context.Context, serviceID int64) ([]models.ServicePrice, error)
+	GetPriceAt(ctx context.Context, serviceID int64, at time.Time) (*models.ServicePrice, error)
+	GetTrashed(ctx context.Context, id int64) (*models.Service, error)
+	Restore(ctx context.Context, id int64) error
+	Reorder(ctx context.Context, ids []int64) error
+	MoveToCategory(ctx context.Context, fromID, toID int64) error
+}
+
+const serviceColumns = `s.service_id, s.category_id, c.name AS category_name, s.name, s.slug, s.description, ` + serviceActive + ` AS is_active,
+	s.publish_at, s.unpublish_at, s.created_at, s.deleted_at, s.version, s.sort_order,
+	s.duration_minutes, s.price, s.currency, s.pricing_unit, s.min_price, s.max_price,
+	COALESCE(sr.review_count, 0) AS review_count,
+	COALESCE(ROUND(CAST(sr.rating_total AS NUMERIC) / NULLIF(sr.review_count, 0), 2), 0) AS average_rating`
+
+// serviceActive is whether a service is active right now, taking the
+// scheduled transitions the scheduler has yet to apply into account.
+const serviceActive = `live_active(s.is_active, s.publish_at, s.unpublish_at)`
+
+const serviceFrom = `FROM services s JOIN categories c ON s.category_id = c.category_id
+	LEFT JOIN service_ratings sr ON sr.service_id = s.service_id`
+
+// serviceSortOrder is the sort_order of a service being saved by a named
+// UPDATE: its current one, or last place in its category when it moves to
+// another.
+const serviceSortOrder = `CASE WHEN category_id = :category_id THEN sort_order
+	ELSE (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM services WHERE category_id = :category_id) END`
+
+// categorySubtree selects the IDs of the category given as its ? argument
+// and of every category nested below it.
+const categorySubtree = `(
+	WITH RECURSIVE subtree AS (
+		SELECT category_id FROM categories WHERE category_id = ?
+		UNION
+		SELECT ch.category_id
+		FROM categories ch
+		JOIN subtree st ON ch.parent_id = st.category_id
+	)
+	SELECT category_id FROM subtree
+)`
+
+var serviceSortKeys = map[string]sortKey[models.Service]{
+	models.SortManual: {
+		columns: []string{"s.sort_order", "s.name", "s.service_id"},
+		values: func(s *models.Service) []string {
+			return []string{strconv.Itoa(s.SortOrder), s.Name, strconv.FormatInt(s.ID, 10)}
+		},
+	},
+	models.SortName: {
+		columns: []string{"s.name", "s.service_id"},
+		values: func(s *models.Service) []string {
+			return []string{s.Name, strconv.FormatInt(s.ID, 10)}
+		},
+	},
+	models.SortID: {
+		columns: []string{"s.service_id"},
+		values: func(s *models.Service) []string {
+			return []string{strconv.FormatInt(s.ID, 10)}
+		},
+	},
+	models.SortCreated: {
+		columns: []string{"s.created_at", "s.service_id"},
+		values: func(s *models.Service) []string {
+			return []string{s.CreatedAt.Format(time.RFC3339Nano), strconv.FormatInt(s.ID, 10)}
+		},
+	},
+}
+
+// servicePatchColumns are the columns Patch may write.
+var servicePatchColumns = []string{
+	"category_id", "name", "slug", "description", "is_active", "publish_at", "unpublish_at", "duration_minutes",
+	"price", "currency", "pricing_unit", "min_price", "max_price",
 }
 
 type serviceRepo struct {
 	db *sqlx.DB
+	tx Transactor
 }
 
 func NewServiceRepo(db *sqlx.DB) ServiceRepo {
-	return &serviceRepo{db: db}
+	return &serviceRepo{db: db, tx: NewTransactor(db)}
 }
 
 func (r *serviceRepo) Create(ctx context.Context, service *models.Service) error {
 	query := `
-		INSERT INTO services (category_id, name, description, is_active)
-		VALUES (:category_id, :name, :description, :is_active)
-		RETURNING service_id
+		INSERT INTO services (category_id, name, slug, description, is_active, publish_at, unpublish_at,
+		                      duration_minutes, price, currency, pricing_unit, min_price, max_price, sort_order)
+		VALUES (:category_id, :name, :slug, :description, :is_active, :publish_at, :unpublish_at, :duration_minutes,
+		        :price, :currency, :pricing_unit, :min_price, :max_price,
+		        (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM services WHERE category_id = :category_id))
+		RETURNING service_id, created_at, version, sort_order
 	`
-	stmt, err := r.db.PrepareNamedContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to prepare query: %w", err)
-	}
-	defer stmt.Close()
-	
-	return stmt.GetContext(ctx, service, service)
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := namedGet(ctx, conn(ctx, r.db), service, query, service); err != nil {
+			return err
+		}
+		return r.recordPrice(ctx, service)
+	})
 }
 
 func (r *serviceRepo) GetByID(ctx context.Context, id int64) (*models.Service, error) {
 	var service models.Service
-	query := `
-		SELECT s.*, c.name as category_name 
-		FROM services s
-		JOIN categories c ON s.category_id = c.category_id
-		WHERE service_id = $1
-	`
-	err := r.db.GetContext(ctx, &service, query, id)
+	query := `SELECT ` + serviceColumns + ` ` + serviceFrom + ` WHERE s.service_id = $1 AND s.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &service, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &service, err
+}
+
+func (r *serviceRepo) GetByName(ctx context.Context, name string) (*models.Service, error) {
+	var service models.Service
+	query := `SELECT ` + serviceColumns + ` ` + serviceFrom + ` WHERE s.name = $1 AND s.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &service, query, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &service, err
+}
+
+// GetBySlug returns the live service with the given slug, or nil.
+func (r *serviceRepo) GetBySlug(ctx context.Context, slug string) (*models.Service, error) {
+	var service models.Service
+	query := `SELECT ` + serviceColumns + ` ` + serviceFrom + ` WHERE s.slug = $1 AND s.deleted_at IS NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &service, query, slug)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return &service, err
 }
 
-func (r *serviceRepo) GetByCategory(ctx context.Context, categoryID int64) ([]models.Service, error) {
+// GetByCategory returns the services of a category, or of it and every
+// category nested below it, matching the filters of opts. It does not page.
+func (r *serviceRepo) GetByCategory(ctx context.Context, categoryID int64, includeDescendants bool, opts models.ListOptions) ([]models.Service, error) {
+	var q listQuery
+	opts.CategoryID = nil
+	filterServices(&q, opts)
+	if includeDescendants {
+		q.where("s.category_id IN "+categorySubtree, categoryID)
+	} else {
+		q.where("s.category_id = ?", categoryID)
+	}
+
+	query := r.db.Rebind(`SELECT ` + serviceColumns + ` ` + serviceFrom + q.clause() + ` ORDER BY s.sort_order, s.name`)
 	var services []models.Service
-	query := `
-		SELECT * FROM services 
-		WHERE category_id = $1 
-		ORDER BY name
-	`
-	err := r.db.SelectContext(ctx, &services, query, categoryID)
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &services, query, q.args...)
 	return services, err
 }
 
-func (r *serviceRepo) GetAll(ctx context.Context) ([]models.Service, error) {
+func (r *serviceRepo) GetAll(ctx context.Context, opts models.ListOptions) (*models.Page[models.Service], error) {
+	key, err := lookupSortKey(serviceSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	filterServices(&q, opts)
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT %s %s%s ORDER BY %s LIMIT %d`,
+		serviceColumns, serviceFrom, q.clause(), orderBy, opts.Limit+1,
+	))
 	var services []models.Service
-	query := `
-		SELECT s.*, c.name as category_name 
-		FROM services s
-		JOIN categories c ON s.category_id = c.category_id
-		ORDER BY s.name
-	`
-	err := r.db.SelectContext(ctx, &services, query)
-	return services, err
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &services, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(services, key, opts), nil
 }
 
+// filterServices adds the conditions selecting the live services matching
+// the filters of opts to q.
+func filterServices(q *listQuery, opts models.ListOptions) {
+	q.where("s.deleted_at IS NULL")
+	if opts.IsActive != nil {
+		q.where(serviceActive+" = ?", *opts.IsActive)
+	}
+	if opts.CategoryID != nil {
+		q.where("s.category_id = ?", *opts.CategoryID)
+	}
+	if opts.Near != nil || opts.PostalCode != "" {
+		var tests []string
+		var args []any
+		if opts.PostalCode != "" {
+			tests = append(tests, "(a.kind = 'postal_codes' AND ? = ANY(a.postal_codes))")
+			args = append(args, opts.PostalCode)
+		}
+		if opts.Near != nil {
+			tests = append(tests, "(a.kind = 'radius' AND haversine_km(a.center_lat, a.center_lng, ?, ?) <= a.radius_km)")
+			args = append(args, opts.Near.Lat, opts.Near.Lng)
+		}
+		q.where(fmt.Sprintf(serviceAreaMatch, strings.Join(tests, " OR ")), args...)
+	}
+	if len(opts.Tags) > 0 {
+		tagged := `SELECT %s FROM service_tags st JOIN tags t ON t.tag_id = st.tag_id
+			WHERE st.service_id = s.service_id AND t.slug = ANY(?)`
+		if opts.TagMatch == models.TagMatchAll {
+			q.where("("+fmt.Sprintf(tagged, "COUNT(*)")+") = ?", pq.Array(opts.Tags), len(opts.Tags))
+		} else {
+			q.where("EXISTS ("+fmt.Sprintf(tagged, "1")+")", pq.Array(opts.Tags))
+		}
+	}
+}
+
+// Update saves a service if it is still at service.Version, which is then set
+// to the new version. A service moved to another category goes to the end of
+// it. It returns sql.ErrNoRows when the service is gone or was changed in the
+// meantime.
 func (r *serviceRepo) Update(ctx context.Context, service *models.Service) error {
 	query := `
 		UPDATE services 
 		SET category_id = :category_id,
 		    name = :name,
+		    slug = :slug,
 		    description = :description,
-		    is_active = :is_active
-		WHERE service_id = :service_id
+		    is_active = :is_active,
+		    publish_at = :publish_at,
+		    unpublish_at = :unpublish_at,
+		    duration_minutes = :duration_minutes,
+		    price = :price,
+		    currency = :currency,
+		    pricing_unit = :pricing_unit,
+		    min_price = :min_price,
+		    max_price = :max_price,
+		    sort_order = ` + serviceSortOrder + `
+		WHERE service_id = :service_id AND deleted_at IS NULL AND version = :version
+		RETURNING version, sort_order
+	`
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := namedGet(ctx, conn(ctx, r.db), service, query, service); err != nil {
+			return err
+		}
+		return r.recordPrice(ctx, service)
+	})
+}
+
+// Patch is Update restricted to the given columns, leaving the rest of the
+// row as it is.
+func (r *serviceRepo) Patch(ctx context.Context, service *models.Service, columns []string) error {
+	set, err := setClause(servicePatchColumns, columns)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(columns, "category_id") {
+		set += ", sort_order = " + serviceSortOrder
+	}
+	query := `
+		UPDATE services
+		SET ` + set + `
+		WHERE service_id = :service_id AND deleted_at IS NULL AND version = :version
+		RETURNING version, sort_order
 	`
-	result, err := r.db.NamedExecContext(ctx, query, service)
+	return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := namedGet(ctx, conn(ctx, r.db), service, query, service); err != nil {
+			return err
+		}
+		return r.recordPrice(ctx, service)
+	})
+}
+
+// Delete moves a service to the trash if it is still at the given version.
+// It stays there until restored or purged.
+func (r *serviceRepo) Delete(ctx context.Context, id, version int64) error {
+	query := `UPDATE services SET deleted_at = NOW() WHERE service_id = $1 AND deleted_at IS NULL AND version = $2`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -99,16 +301,125 @@ func (r *serviceRepo) Update(ctx context.Context, service *models.Service) error
 	return nil
 }
 
-func (r *serviceRepo) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM services WHERE service_id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+func (r *serviceRepo) GetPriceHistory(ctx context.Context, serviceID int64) ([]models.ServicePrice, error) {
+	var prices []models.ServicePrice
+	query := `
+		SELECT price_id, service_id, price, currency, pricing_unit, min_price, max_price, effective_from
+		FROM service_prices
+		WHERE service_id = $1
+		ORDER BY effective_from DESC, price_id DESC
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &prices, query, serviceID)
+	return prices, err
+}
+
+func (r *serviceRepo) GetPriceAt(ctx context.Context, serviceID int64, at time.Time) (*models.ServicePrice, error) {
+	var price models.ServicePrice
+	query := `
+		SELECT price_id, service_id, price, currency, pricing_unit, min_price, max_price, effective_from
+		FROM service_prices
+		WHERE service_id = $1 AND effective_from <= $2
+		ORDER BY effective_from DESC, price_id DESC
+		LIMIT 1
+	`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &price, query, serviceID, at)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &price, err
+}
+
+// GetTrashed returns a service that is in the trash, or nil if there is no
+// such trashed service.
+func (r *serviceRepo) GetTrashed(ctx context.Context, id int64) (*models.Service, error) {
+	var service models.Service
+	query := `SELECT ` + serviceColumns + ` ` + serviceFrom + ` WHERE s.service_id = $1 AND s.deleted_at IS NOT NULL`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &service, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &service, err
+}
+
+// Restore takes a service back out of the trash. It fails with ErrDuplicate
+// when a live service has taken its name in the meantime.
+func (r *serviceRepo) Restore(ctx context.Context, id int64) error {
+	query := `UPDATE services SET deleted_at = NULL WHERE service_id = $1 AND deleted_at IS NOT NULL`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
-	
+
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
 		return sql.ErrNoRows
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// Reorder numbers the given services from 1 in the order listed. Rows
+// already in place are left alone, so their version does not change.
+func (r *serviceRepo) Reorder(ctx context.Context, ids []int64) error {
+	query := `
+		UPDATE services s
+		SET sort_order = o.position
+		FROM unnest(CAST($1 AS BIGINT[])) WITH ORDINALITY AS o(service_id, position)
+		WHERE s.service_id = o.service_id AND s.sort_order <> o.position
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
+
+// MoveToCategory moves every live service of one category to the end of
+// another, keeping their order.
+func (r *serviceRepo) MoveToCategory(ctx context.Context, fromID, toID int64) error {
+	query := `
+		UPDATE services s
+		SET category_id = $2,
+		    sort_order = s.sort_order + (SELECT COALESCE(MAX(sort_order), 0) FROM services WHERE category_id = $2)
+		WHERE s.category_id = $1 AND s.deleted_at IS NULL
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, fromID, toID)
+	return err
+}
+
+// recordPrice appends the service's current pricing to its history unless it
+// matches the latest entry already there.
+func (r *serviceRepo) recordPrice(ctx context.Context, service *models.Service) error {
+	var latest models.ServicePrice
+	query := `
+		SELECT price_id, service_id, price, currency, pricing_unit, min_price, max_price, effective_from
+		FROM service_prices
+		WHERE service_id = $1
+		ORDER BY effective_from DESC, price_id DESC
+		LIMIT 1
+	`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &latest, query, service.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read price history: %w", err)
+	}
+	if err == nil &&
+		latest.Price == service.Price &&
+		latest.Currency == service.Currency &&
+		latest.PricingUnit == service.PricingUnit &&
+		equalInt64Ptr(latest.MinPrice, service.MinPrice) &&
+		equalInt64Ptr(latest.MaxPrice, service.MaxPrice) {
+		return nil
+	}
+
+	query = `
+		INSERT INTO service_prices (service_id, price, currency, pricing_unit, min_price, max_price)
+		VALUES (:service_id, :price, :currency, :pricing_unit, :min_price, :max_price)
+	`
+	if _, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, service); err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+func equalInt64Ptr(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SlugRepo answers questions about the slugs of categories and services
+// across both the slugs in use and those retired into redirects.
+type SlugRepo interface {
+	Taken(ctx context.Context, entityType string, slugs []string) ([]string, error)
+	Owner(ctx context.Context, entityType, slug string) (int64, error)
+	Redirect(ctx context.Context, entityType, slug string) (id int64, current string, err error)
+}
+
+type slugRepo struct {
+	db *sqlx.DB
+}
+
+func NewSlugRepo(db *sqlx.DB) SlugRepo {
+	return &slugRepo{db: db}
+}
+
+// Taken returns those of slugs that an entity of the type has, in the trash
+// or not, or used to have.
+func (r *slugRepo) Taken(ctx context.Context, entityType string, slugs []string) ([]string, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	var taken []string
+	query := `
+		SELECT slug FROM ` + table[0] + ` WHERE slug = ANY($1)
+		UNION
+		SELECT slug FROM slug_redirects WHERE entity_type = $2 AND slug = ANY($1)
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &taken, query, pq.Array(slugs), entityType)
+	return taken, err
+}
+
+// Owner returns the ID of the entity of the type that has slug, in the trash
+// or not, or 0 if there is none.
+func (r *slugRepo) Owner(ctx context.Context, entityType, slug string) (int64, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return 0, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	var id int64
+	query := `SELECT ` + table[1] + ` FROM ` + table[0] + ` WHERE slug = $1`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &id, query, slug)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+// Redirect returns the live entity that used to have slug together with its
+// current slug, or 0 if slug leads nowhere.
+func (r *slugRepo) Redirect(ctx context.Context, entityType, slug string) (int64, string, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return 0, "", fmt.Errorf("unknown entity type %q", entityType)
+	}
+	var target struct {
+		ID   int64  `db:"id"`
+		Slug string `db:"slug"`
+	}
+	query := `
+		SELECT e.` + table[1] + ` AS id, e.slug
+		FROM slug_redirects r
+		JOIN ` + table[0] + ` e ON e.` + table[1] + ` = r.entity_id AND e.deleted_at IS NULL
+		WHERE r.entity_type = $1 AND r.slug = $2
+	`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &target, query, entityType, slug)
+	if err == sql.ErrNoRows {
+		return 0, "", nil
+	}
+	return target.ID, target.Slug, err
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TagRepo interface {
+	Create(ctx context.Context, tag *models.Tag) error
+	GetByID(ctx context.Context, id int64) (*models.Tag, error)
+	GetAll(ctx context.Context, sort string) ([]models.Tag, error)
+	GetByService(ctx context.Context, serviceID int64) ([]models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id int64) error
+	Attach(ctx context.Context, serviceID, tagID int64) error
+	Detach(ctx context.Context, serviceID, tagID int64) error
+}
+
+// tagSelect reads tags with their usage, counting only services that are
+// not in the trash. Queries add their conditions and GROUP BY t.tag_id.
+const tagSelect = `
+	SELECT t.tag_id, t.name, t.slug, t.created_at, COUNT(s.service_id) AS service_count
+	FROM tags t
+	LEFT JOIN service_tags st ON st.tag_id = t.tag_id
+	LEFT JOIN services s ON s.service_id = st.service_id AND s.deleted_at IS NULL
+`
+
+// tagOrders are the ORDER BY clauses of the sorts GetAll accepts.
+var tagOrders = map[string]string{
+	models.SortName:  "t.name, t.tag_id",
+	models.SortUsage: "service_count DESC, t.name, t.tag_id",
+}
+
+type tagRepo struct {
+	db *sqlx.DB
+}
+
+func NewTagRepo(db *sqlx.DB) TagRepo {
+	return &tagRepo{db: db}
+}
+
+// Create adds a tag. It fails with ErrDuplicate when the slug is taken.
+func (r *tagRepo) Create(ctx context.Context, tag *models.Tag) error {
+	query := `
+		INSERT INTO tags (name, slug)
+		VALUES (:name, :slug)
+		RETURNING tag_id, created_at
+	`
+	return translateError(namedGet(ctx, conn(ctx, r.db), tag, query, tag))
+}
+
+func (r *tagRepo) GetByID(ctx context.Context, id int64) (*models.Tag, error) {
+	var tag models.Tag
+	query := tagSelect + ` WHERE t.tag_id = $1 GROUP BY t.tag_id`
+	err := sqlx.GetContext(ctx, conn(ctx, r.db), &tag, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &tag, err
+}
+
+// GetAll returns every tag in the order of sort, name or usage.
+func (r *tagRepo) GetAll(ctx context.Context, sort string) ([]models.Tag, error) {
+	order, ok := tagOrders[sort]
+	if !ok {
+		order = tagOrders[models.SortName]
+	}
+	var tags []models.Tag
+	query := tagSelect + ` GROUP BY t.tag_id ORDER BY ` + order
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &tags, query)
+	return tags, err
+}
+
+func (r *tagRepo) GetByService(ctx context.Context, serviceID int64) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := tagSelect + `
+		WHERE t.tag_id IN (SELECT tag_id FROM service_tags WHERE service_id = $1)
+		GROUP BY t.tag_id
+		ORDER BY t.name, t.tag_id
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &tags, query, serviceID)
+	return tags, err
+}
+
+// Update renames a tag. It fails with ErrDuplicate when the slug is taken
+// and with sql.ErrNoRows when the tag does not exist.
+func (r *tagRepo) Update(ctx context.Context, tag *models.Tag) error {
+	query := `UPDATE tags SET name = :name, slug = :slug WHERE tag_id = :tag_id`
+	result, err := sqlx.NamedExecContext(ctx, conn(ctx, r.db), query, tag)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete removes a tag from every service carrying it and then the tag.
+func (r *tagRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM tags WHERE tag_id = $1`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Attach puts a tag on a service. Attaching a tag the service already has
+// does nothing.
+func (r *tagRepo) Attach(ctx context.Context, serviceID, tagID int64) error {
+	query := `
+		INSERT INTO service_tags (service_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT (service_id, tag_id) DO NOTHING
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, serviceID, tagID)
+	return err
+}
+
+// Detach takes a tag off a service. Detaching a tag the service does not
+// have does nothing.
+func (r *tagRepo) Detach(ctx context.Context, serviceID, tagID int64) error {
+	query := `DELETE FROM service_tags WHERE service_id = $1 AND tag_id = $2`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, serviceID, tagID)
+	return err
+}
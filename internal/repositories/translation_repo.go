@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TranslationRepo interface {
+	GetByEntity(ctx context.Context, entityType string, entityID int64) ([]models.Translation, error)
+	GetForEntities(ctx context.Context, entityType string, entityIDs []int64, locales []string) ([]models.Translation, error)
+	Upsert(ctx context.Context, translation *models.Translation) error
+	DeleteLocale(ctx context.Context, entityType string, entityID int64, locale string) (int64, error)
+	GetCoverage(ctx context.Context, entityType string, locales []string) ([]models.MissingTranslation, error)
+}
+
+const translationColumns = `entity_type, entity_id, locale, field, value, updated_at`
+
+// entityTables maps entity types to their table and key column.
+var entityTables = map[string][2]string{
+	models.EntityCategory: {"categories", "category_id"},
+	models.EntityService:  {"services", "service_id"},
+}
+
+type translationRepo struct {
+	db *sqlx.DB
+}
+
+func NewTranslationRepo(db *sqlx.DB) TranslationRepo {
+	return &translationRepo{db: db}
+}
+
+func (r *translationRepo) GetByEntity(ctx context.Context, entityType string, entityID int64) ([]models.Translation, error) {
+	var translations []models.Translation
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translations
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY locale, field
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &translations, query, entityType, entityID)
+	return translations, err
+}
+
+// GetForEntities returns the translations of several entities restricted to
+// the given locales.
+func (r *translationRepo) GetForEntities(ctx context.Context, entityType string, entityIDs []int64, locales []string) ([]models.Translation, error) {
+	var translations []models.Translation
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translations
+		WHERE entity_type = $1 AND entity_id = ANY($2) AND locale = ANY($3)
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &translations, query,
+		entityType, pq.Array(entityIDs), pq.Array(locales))
+	return translations, err
+}
+
+func (r *translationRepo) Upsert(ctx context.Context, translation *models.Translation) error {
+	query := `
+		INSERT INTO translations (entity_type, entity_id, locale, field, value)
+		VALUES (:entity_type, :entity_id, :locale, :field, :value)
+		ON CONFLICT (entity_type, entity_id, locale, field)
+		DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+		RETURNING updated_at
+	`
+	return namedGet(ctx, conn(ctx, r.db), &translation.UpdatedAt, query, translation)
+}
+
+// DeleteLocale removes every translated field of an entity in one locale and
+// reports how many were removed.
+func (r *translationRepo) DeleteLocale(ctx context.Context, entityType string, entityID int64, locale string) (int64, error) {
+	query := `DELETE FROM translations WHERE entity_type = $1 AND entity_id = $2 AND locale = $3`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, entityType, entityID, locale)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetCoverage lists every entity of a type with the fields that have no
+// translation in any of the given locales. Empty descriptions need none.
+func (r *translationRepo) GetCoverage(ctx context.Context, entityType string, locales []string) ([]models.MissingTranslation, error) {
+	table, ok := entityTables[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported entity type %q", entityType)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT CAST($1 AS VARCHAR) AS entity_type, e.%[2]s AS entity_id, e.name,
+		       ARRAY_REMOVE(ARRAY[
+		           CASE WHEN NOT EXISTS (
+		               SELECT 1 FROM translations t
+		               WHERE t.entity_type = $1 AND t.entity_id = e.%[2]s
+		                 AND t.field = 'name' AND t.locale = ANY($2)
+		           ) THEN 'name' END,
+		           CASE WHEN e.description <> '' AND NOT EXISTS (
+		               SELECT 1 FROM translations t
+		               WHERE t.entity_type = $1 AND t.entity_id = e.%[2]s
+		                 AND t.field = 'description' AND t.locale = ANY($2)
+		           ) THEN 'description' END
+		       ], NULL) AS fields
+		FROM %[1]s e
+		WHERE e.deleted_at IS NULL
+		ORDER BY e.%[2]s
+	`, table[0], table[1])
+
+	var coverage []models.MissingTranslation
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &coverage, query, entityType, pq.Array(locales))
+	return coverage, err
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"server/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TrashRepo interface {
+	GetAll(ctx context.Context, entityType string, opts models.ListOptions) (*models.Page[models.TrashItem], error)
+	GetExpiredMedia(ctx context.Context, before time.Time) ([]models.Media, error)
+	GetRemainingMedia(ctx context.Context, ids []int64) ([]int64, error)
+	PurgeServices(ctx context.Context, before time.Time) (int64, error)
+	PurgeCategories(ctx context.Context, before time.Time) (int64, error)
+}
+
+// trashItems is the union of trashed categories and services, aliased t by
+// queries selecting from it. kept_because says why the purge keeps an item:
+// a service bookings refer to, or a category with such a service anywhere
+// below it.
+const trashItems = `(
+	SELECT 'category' AS type, c.category_id AS id, c.name, c.deleted_at,
+	       CASE WHEN EXISTS (
+	           SELECT 1
+	           FROM services s
+	           JOIN bookings b ON b.service_id = s.service_id
+	           WHERE s.category_id IN (
+	               WITH RECURSIVE subtree AS (
+	                   SELECT c.category_id
+	                   UNION
+	                   SELECT ch.category_id
+	                   FROM categories ch
+	                   JOIN subtree st ON ch.parent_id = st.category_id
+	               )
+	               SELECT category_id FROM subtree
+	           )
+	       ) THEN 'bookings refer to services in it' ELSE '' END AS kept_because
+	FROM categories c WHERE c.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'service' AS type, s.service_id AS id, s.name, s.deleted_at,
+	       CASE WHEN EXISTS (SELECT 1 FROM bookings b WHERE b.service_id = s.service_id)
+	       THEN 'bookings refer to it' ELSE '' END AS kept_because
+	FROM services s WHERE s.deleted_at IS NOT NULL
+) t`
+
+var trashSortKeys = map[string]sortKey[models.TrashItem]{
+	models.SortDeletedAt: {
+		columns: []string{"t.deleted_at", "t.type", "t.id"},
+		values: func(t *models.TrashItem) []string {
+			return []string{t.DeletedAt.Format(time.RFC3339Nano), t.Type, strconv.FormatInt(t.ID, 10)}
+		},
+	},
+}
+
+type trashRepo struct {
+	db *sqlx.DB
+}
+
+func NewTrashRepo(db *sqlx.DB) TrashRepo {
+	return &trashRepo{db: db}
+}
+
+func (r *trashRepo) GetAll(ctx context.Context, entityType string, opts models.ListOptions) (*models.Page[models.TrashItem], error) {
+	key, err := lookupSortKey(trashSortKeys, opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var q listQuery
+	if entityType != "" {
+		q.where("t.type = ?", entityType)
+	}
+	orderBy, err := applyKeyset(&q, key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := r.db.Rebind(fmt.Sprintf(
+		`SELECT t.type, t.id, t.name, t.deleted_at, t.kept_because FROM %s%s ORDER BY %s LIMIT %d`,
+		trashItems, q.clause(), orderBy, opts.Limit+1,
+	))
+	var items []models.TrashItem
+	if err := sqlx.SelectContext(ctx, conn(ctx, r.db), &items, query, q.args...); err != nil {
+		return nil, err
+	}
+	return newPage(items, key, opts), nil
+}
+
+// GetExpiredMedia returns the media of every category and service trashed
+// before the cutoff, which a purge may remove along with their owners.
+func (r *trashRepo) GetExpiredMedia(ctx context.Context, before time.Time) ([]models.Media, error) {
+	var media []models.Media
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM media
+		WHERE service_id IN (SELECT service_id FROM services WHERE deleted_at < $1)
+		   OR category_id IN (SELECT category_id FROM categories WHERE deleted_at < $1)
+	`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &media, query, before)
+	return media, err
+}
+
+// GetRemainingMedia returns which of the given media still exist.
+func (r *trashRepo) GetRemainingMedia(ctx context.Context, ids []int64) ([]int64, error) {
+	var remaining []int64
+	query := `SELECT media_id FROM media WHERE media_id = ANY($1)`
+	err := sqlx.SelectContext(ctx, conn(ctx, r.db), &remaining, query, pq.Array(ids))
+	return remaining, err
+}
+
+// PurgeServices permanently deletes services trashed before the cutoff.
+// Services that bookings still refer to are kept, as booking history must
+// survive.
+func (r *trashRepo) PurgeServices(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM services s
+		WHERE s.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM bookings b WHERE b.service_id = s.service_id)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeCategories permanently deletes categories trashed before the cutoff
+// that no remaining service, bundle or subcategory refers to. Trashed subtrees are
+// removed leaf first, one level per pass.
+func (r *trashRepo) PurgeCategories(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM categories c
+		WHERE c.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM services s WHERE s.category_id = c.category_id)
+		  AND NOT EXISTS (SELECT 1 FROM bundles b WHERE b.category_id = c.category_id)
+		  AND NOT EXISTS (SELECT 1 FROM categories ch WHERE ch.parent_id = c.category_id)
+	`
+	var total int64
+	for {
+		result, err := conn(ctx, r.db).ExecContext(ctx, query, before)
+		if err != nil {
+			return total, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += n
+	}
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactor runs a function inside a database transaction. The transaction
+// travels in the context, so repository calls made with that context take
+// part in it. Nested WithinTx calls join the outer transaction inside a
+// savepoint, so their failure undoes only their own work and leaves the
+// outer transaction usable.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// savepointKey carries the savepoint nesting depth.
+type savepointKey struct{}
+
+type transactor struct {
+	db *sqlx.DB
+}
+
+func NewTransactor(db *sqlx.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return withinSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func withinSavepoint(ctx context.Context, tx *sqlx.Tx, fn func(ctx context.Context) error) error {
+	depth, _ := ctx.Value(savepointKey{}).(int)
+	name := fmt.Sprintf("sp_%d", depth+1)
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, savepointKey{}, depth+1)); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name+"; RELEASE SAVEPOINT "+name); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back savepoint: %w", rbErr))
+		}
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// namedGet runs a named query expected to return one row (typically an
+// INSERT ... RETURNING) and scans it into dest.
+func namedGet(ctx context.Context, q sqlx.ExtContext, dest any, query string, arg any) error {
+	query, args, err := q.BindNamed(query, arg)
+	if err != nil {
+		return fmt.Errorf("failed to bind query: %w", err)
+	}
+	return sqlx.GetContext(ctx, q, dest, query, args...)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// maxAreaRadiusKm keeps radius areas to a plausible service distance.
+const maxAreaRadiusKm = 500
+
+type AreaService struct {
+	areaRepo     repositories.AreaRepo
+	serviceRepo  repositories.ServiceRepo
+	providerRepo repositories.ProviderRepo
+}
+
+func NewAreaService(
+	areaRepo repositories.AreaRepo,
+	serviceRepo repositories.ServiceRepo,
+	providerRepo repositories.ProviderRepo,
+) *AreaService {
+	return &AreaService{
+		areaRepo:     areaRepo,
+		serviceRepo:  serviceRepo,
+		providerRepo: providerRepo,
+	}
+}
+
+func (s *AreaService) CreateServiceArea(ctx context.Context, serviceID int64, req *models.ServiceArea) (*models.ServiceArea, error) {
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+
+	req.ServiceID, req.ProviderID = &serviceID, nil
+	return s.create(ctx, req)
+}
+
+func (s *AreaService) CreateProviderArea(ctx context.Context, providerID int64, req *models.ServiceArea) (*models.ServiceArea, error) {
+	provider, err := s.providerRepo.GetByID(ctx, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("provider %w", ErrNotFound)
+	}
+
+	req.ProviderID, req.ServiceID = &providerID, nil
+	return s.create(ctx, req)
+}
+
+func (s *AreaService) ListServiceAreas(ctx context.Context, serviceID int64) ([]models.ServiceArea, error) {
+	areas, err := s.areaRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service areas: %w", err)
+	}
+	return areas, nil
+}
+
+func (s *AreaService) ListProviderAreas(ctx context.Context, providerID int64) ([]models.ServiceArea, error) {
+	areas, err := s.areaRepo.GetByProvider(ctx, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider areas: %w", err)
+	}
+	return areas, nil
+}
+
+func (s *AreaService) DeleteArea(ctx context.Context, id int64) error {
+	if id == 0 {
+		return errors.New("invalid area ID")
+	}
+
+	if err := s.areaRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete area: %w", err)
+	}
+	return nil
+}
+
+func (s *AreaService) create(ctx context.Context, req *models.ServiceArea) (*models.ServiceArea, error) {
+	switch req.Kind {
+	case models.AreaKindPostalCodes:
+		codes := make([]string, 0, len(req.PostalCodes))
+		seen := make(map[string]bool)
+		for _, code := range req.PostalCodes {
+			code = normalizePostalCode(code)
+			if code != "" && !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+		if len(codes) == 0 {
+			return nil, errors.New("postal code area needs at least one postal code")
+		}
+		req.PostalCodes = codes
+		req.CenterLat, req.CenterLng, req.RadiusKm = nil, nil, nil
+
+	case models.AreaKindRadius:
+		if req.CenterLat == nil || req.CenterLng == nil || req.RadiusKm == nil {
+			return nil, errors.New("radius area needs center_lat, center_lng and radius_km")
+		}
+		if err := validateGeoPoint(models.GeoPoint{Lat: *req.CenterLat, Lng: *req.CenterLng}); err != nil {
+			return nil, err
+		}
+		if *req.RadiusKm <= 0 || *req.RadiusKm > maxAreaRadiusKm {
+			return nil, fmt.Errorf("radius must be between 0 and %d km", maxAreaRadiusKm)
+		}
+		req.PostalCodes = nil
+
+	default:
+		return nil, fmt.Errorf("area kind must be %q or %q", models.AreaKindPostalCodes, models.AreaKindRadius)
+	}
+
+	if err := s.areaRepo.Create(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to create area: %w", err)
+	}
+	return req, nil
+}
+
+// normalizePostalCode makes postal codes comparable regardless of case and
+// spacing ("sw1a 1aa" and "SW1A1AA" match).
+func normalizePostalCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+func validateGeoPoint(p models.GeoPoint) error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("latitude %v out of range", p.Lat)
+	}
+	if p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("longitude %v out of range", p.Lng)
+	}
+	return nil
+}
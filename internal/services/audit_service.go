@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"server/internal/audit"
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+type AuditService struct {
+	auditRepo repositories.AuditRepo
+}
+
+func NewAuditService(auditRepo repositories.AuditRepo) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// ListAudit lists audit entries matching filter, most recent first by
+// default.
+func (s *AuditService) ListAudit(ctx context.Context, filter models.AuditFilter, opts models.ListOptions) (*models.Page[models.AuditEntry], error) {
+	if opts.Order == "" {
+		opts.Order = models.OrderDesc
+	}
+	if err := normalizeListOptionsWith(&opts, models.SortCreated); err != nil {
+		return nil, err
+	}
+	if filter.EntityType != "" && filter.EntityType != models.EntityCategory && filter.EntityType != models.EntityService {
+		return nil, fmt.Errorf("%w: unknown entity %q", ErrInvalidListOptions, filter.EntityType)
+	}
+	if filter.EntityID != nil && filter.EntityType == "" {
+		return nil, fmt.Errorf("%w: id requires entity", ErrInvalidListOptions)
+	}
+
+	page, err := s.auditRepo.GetAll(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", listError(err))
+	}
+	return page, nil
+}
+
+// recordAudit appends an entry for a change to an entity, attributed to the
+// actor and request carried by ctx. Before and after are the entity around
+// the change and may be nil. Called inside the transaction making the change
+// so that the two commit or roll back together.
+func recordAudit(ctx context.Context, repo repositories.AuditRepo, action, entityType string, id int64, before, after any) error {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", entityType, err)
+	}
+
+	entry := &models.AuditEntry{
+		Actor:      audit.Actor(ctx),
+		Admin:      audit.IsAdmin(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   id,
+		RequestID:  audit.RequestID(ctx),
+	}
+	if entry.Before, err = json.Marshal(before); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", entityType, err)
+	}
+	if entry.After, err = json.Marshal(after); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", entityType, err)
+	}
+	if entry.Changes, err = json.Marshal(changes); err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	if err := repo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
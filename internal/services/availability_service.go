@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/availability"
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// maxAvailabilityRange bounds how far ahead a single availability query may
+// look, since slot computation walks every day in the range.
+const maxAvailabilityRange = 31 * 24 * time.Hour
+
+type AvailabilityService struct {
+	availabilityRepo repositories.AvailabilityRepo
+	providerRepo     repositories.ProviderRepo
+	serviceRepo      repositories.ServiceRepo
+}
+
+func NewAvailabilityService(
+	availabilityRepo repositories.AvailabilityRepo,
+	providerRepo repositories.ProviderRepo,
+	serviceRepo repositories.ServiceRepo,
+) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		providerRepo:     providerRepo,
+		serviceRepo:      serviceRepo,
+	}
+}
+
+func (s *AvailabilityService) GetWorkingHours(ctx context.Context, providerID int64) ([]models.WorkingHours, error) {
+	if err := s.checkProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	hours, err := s.availabilityRepo.GetWorkingHours(ctx, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+	return hours, nil
+}
+
+// SetWorkingHours replaces a provider's whole weekly schedule.
+func (s *AvailabilityService) SetWorkingHours(ctx context.Context, providerID int64, hours []models.WorkingHours) error {
+	if err := s.checkProvider(ctx, providerID); err != nil {
+		return err
+	}
+
+	perDay := make(map[int][]availability.Window)
+	for _, h := range hours {
+		if h.Weekday < 0 || h.Weekday > 6 {
+			return fmt.Errorf("invalid weekday %d, want 0 (Sunday) to 6", h.Weekday)
+		}
+		perDay[h.Weekday] = append(perDay[h.Weekday], toWindow(h.HoursWindow))
+	}
+	for day, windows := range perDay {
+		if err := availability.ValidateWindows(windows); err != nil {
+			return fmt.Errorf("%s: %w", time.Weekday(day), err)
+		}
+	}
+
+	if err := s.availabilityRepo.ReplaceWorkingHours(ctx, providerID, hours); err != nil {
+		return fmt.Errorf("failed to set working hours: %w", err)
+	}
+	return nil
+}
+
+func (s *AvailabilityService) ListExceptions(ctx context.Context, providerID int64, from, to string) ([]models.AvailabilityException, error) {
+	if err := s.checkProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+	if err := availability.ValidateDateRange(availability.DateRange{From: from, To: to}); err != nil {
+		return nil, err
+	}
+
+	exceptions, err := s.availabilityRepo.GetExceptions(ctx, providerID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exceptions: %w", err)
+	}
+	return exceptions, nil
+}
+
+// SetException overrides a provider's weekly hours on one date. No windows
+// means the provider is off that day.
+func (s *AvailabilityService) SetException(ctx context.Context, exception *models.AvailabilityException) error {
+	if err := s.checkProvider(ctx, exception.ProviderID); err != nil {
+		return err
+	}
+	if _, err := time.Parse(availability.DateLayout, exception.Date); err != nil {
+		return fmt.Errorf("invalid date %q, want YYYY-MM-DD", exception.Date)
+	}
+
+	windows := make([]availability.Window, len(exception.Windows))
+	for i, w := range exception.Windows {
+		windows[i] = toWindow(w)
+	}
+	if err := availability.ValidateWindows(windows); err != nil {
+		return err
+	}
+
+	if err := s.availabilityRepo.SetException(ctx, exception); err != nil {
+		return fmt.Errorf("failed to set exception: %w", err)
+	}
+	return nil
+}
+
+func (s *AvailabilityService) DeleteException(ctx context.Context, providerID int64, date string) error {
+	if err := s.availabilityRepo.DeleteException(ctx, providerID, date); err != nil {
+		return fmt.Errorf("failed to delete exception: %w", err)
+	}
+	return nil
+}
+
+func (s *AvailabilityService) ListBlackouts(ctx context.Context, providerID int64, from, to string) ([]models.Blackout, error) {
+	if err := s.checkProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+	if err := availability.ValidateDateRange(availability.DateRange{From: from, To: to}); err != nil {
+		return nil, err
+	}
+
+	blackouts, err := s.availabilityRepo.GetBlackouts(ctx, providerID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blackouts: %w", err)
+	}
+	return blackouts, nil
+}
+
+func (s *AvailabilityService) CreateBlackout(ctx context.Context, blackout *models.Blackout) (*models.Blackout, error) {
+	if err := s.checkProvider(ctx, blackout.ProviderID); err != nil {
+		return nil, err
+	}
+	if err := availability.ValidateDateRange(availability.DateRange{From: blackout.StartDate, To: blackout.EndDate}); err != nil {
+		return nil, err
+	}
+
+	if err := s.availabilityRepo.CreateBlackout(ctx, blackout); err != nil {
+		return nil, fmt.Errorf("failed to create blackout: %w", err)
+	}
+	return blackout, nil
+}
+
+func (s *AvailabilityService) DeleteBlackout(ctx context.Context, providerID, blackoutID int64) error {
+	if err := s.availabilityRepo.DeleteBlackout(ctx, providerID, blackoutID); err != nil {
+		return fmt.Errorf("failed to delete blackout: %w", err)
+	}
+	return nil
+}
+
+// GetServiceAvailability computes the open slots for a service between from
+// and to, per active provider offering it. Slots last the service duration
+// and start every step (the duration when step is zero).
+func (s *AvailabilityService) GetServiceAvailability(ctx context.Context, serviceID int64, from, to time.Time, providerID *int64, step time.Duration) ([]models.ProviderAvailability, error) {
+	if !to.After(from) {
+		return nil, errors.New("availability range must end after it starts")
+	}
+	if to.Sub(from) > maxAvailabilityRange {
+		return nil, fmt.Errorf("availability range cannot exceed %d days", int(maxAvailabilityRange.Hours()/24))
+	}
+	if step < 0 {
+		return nil, errors.New("slot step must be positive")
+	}
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+	duration := time.Duration(service.DurationMinutes) * time.Minute
+
+	offers, err := s.providerRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service providers: %w", err)
+	}
+
+	result := []models.ProviderAvailability{}
+	for _, offer := range offers {
+		if !offer.IsActive || (providerID != nil && offer.ID != *providerID) {
+			continue
+		}
+
+		schedule, err := s.schedule(ctx, &offer.Provider, from, to)
+		if err != nil {
+			return nil, err
+		}
+		busy, err := s.availabilityRepo.GetBusySlots(ctx, offer.ID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bookings: %w", err)
+		}
+		busySlots := make([]availability.Slot, len(busy))
+		for i, b := range busy {
+			busySlots[i] = availability.Slot{Start: b.Start, End: b.End}
+		}
+
+		slots := []models.TimeSlot{}
+		for _, slot := range schedule.Slots(from, to, duration, step, busySlots) {
+			slots = append(slots, models.TimeSlot{Start: slot.Start, End: slot.End})
+		}
+		result = append(result, models.ProviderAvailability{
+			ProviderID:   offer.ID,
+			ProviderName: offer.Name,
+			Timezone:     offer.Timezone,
+			Slots:        slots,
+		})
+	}
+	return result, nil
+}
+
+// schedule loads everything that shapes a provider's working time between
+// from and to.
+func (s *AvailabilityService) schedule(ctx context.Context, provider *models.Provider, from, to time.Time) (availability.Schedule, error) {
+	loc, err := time.LoadLocation(provider.Timezone)
+	if err != nil {
+		return availability.Schedule{}, fmt.Errorf("provider %d has invalid timezone %q", provider.ID, provider.Timezone)
+	}
+	// Pad by a day either side: local dates can differ from UTC dates.
+	fromDate := from.In(loc).AddDate(0, 0, -1).Format(availability.DateLayout)
+	toDate := to.In(loc).AddDate(0, 0, 1).Format(availability.DateLayout)
+
+	hours, err := s.availabilityRepo.GetWorkingHours(ctx, provider.ID)
+	if err != nil {
+		return availability.Schedule{}, fmt.Errorf("failed to get working hours: %w", err)
+	}
+	exceptions, err := s.availabilityRepo.GetExceptions(ctx, provider.ID, fromDate, toDate)
+	if err != nil {
+		return availability.Schedule{}, fmt.Errorf("failed to get exceptions: %w", err)
+	}
+	blackouts, err := s.availabilityRepo.GetBlackouts(ctx, provider.ID, fromDate, toDate)
+	if err != nil {
+		return availability.Schedule{}, fmt.Errorf("failed to get blackouts: %w", err)
+	}
+
+	schedule := availability.Schedule{
+		Location:   loc,
+		Weekly:     make(map[time.Weekday][]availability.Window),
+		Exceptions: make(map[string][]availability.Window),
+	}
+	for _, h := range hours {
+		day := time.Weekday(h.Weekday)
+		schedule.Weekly[day] = append(schedule.Weekly[day], toWindow(h.HoursWindow))
+	}
+	for _, e := range exceptions {
+		windows := []availability.Window{}
+		for _, w := range e.Windows {
+			windows = append(windows, toWindow(w))
+		}
+		schedule.Exceptions[e.Date] = windows
+	}
+	for _, b := range blackouts {
+		schedule.Blackouts = append(schedule.Blackouts, availability.DateRange{From: b.StartDate, To: b.EndDate})
+	}
+	return schedule, nil
+}
+
+func (s *AvailabilityService) checkProvider(ctx context.Context, providerID int64) error {
+	provider, err := s.providerRepo.GetByID(ctx, providerID)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	if provider == nil {
+		return fmt.Errorf("provider %w", ErrNotFound)
+	}
+	return nil
+}
+
+func toWindow(w models.HoursWindow) availability.Window {
+	return availability.Window{Start: availability.Clock(w.Start), End: availability.Clock(w.End)}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// bookingTransitions is the booking state machine: the statuses each status
+// may move to. Completed and cancelled bookings are final.
+var bookingTransitions = map[string][]string{
+	models.BookingRequested:  {models.BookingConfirmed, models.BookingCancelled},
+	models.BookingConfirmed:  {models.BookingInProgress, models.BookingCancelled},
+	models.BookingInProgress: {models.BookingCompleted},
+}
+
+type BookingService struct {
+	tx           repositories.Transactor
+	bookingRepo  repositories.BookingRepo
+	serviceRepo  repositories.ServiceRepo
+	categoryRepo repositories.CategoryRepo
+	providerRepo repositories.ProviderRepo
+}
+
+func NewBookingService(
+	tx repositories.Transactor,
+	bookingRepo repositories.BookingRepo,
+	serviceRepo repositories.ServiceRepo,
+	categoryRepo repositories.CategoryRepo,
+	providerRepo repositories.ProviderRepo,
+) *BookingService {
+	return &BookingService{
+		tx:           tx,
+		bookingRepo:  bookingRepo,
+		serviceRepo:  serviceRepo,
+		categoryRepo: categoryRepo,
+		providerRepo: providerRepo,
+	}
+}
+
+func (s *BookingService) CreateBooking(ctx context.Context, req *models.Booking) (*models.Booking, error) {
+	req.CustomerName = strings.TrimSpace(req.CustomerName)
+	if req.CustomerName == "" {
+		return nil, errors.New("customer name cannot be empty")
+	}
+	addr, err := mail.ParseAddress(req.CustomerEmail)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customer email: %w", err)
+	}
+	req.CustomerEmail = addr.Address
+
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return nil, errors.New("booking start and end times are required")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("booking must end after it starts")
+	}
+	if req.StartsAt.Before(time.Now()) {
+		return nil, errors.New("booking cannot start in the past")
+	}
+
+	req.Status = models.BookingRequested
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// The service and provider stay locked until the booking is made,
+		// so neither can be deactivated once checked.
+		if err := s.bookingRepo.LockService(ctx, req.ServiceID); err != nil {
+			return fmt.Errorf("failed to lock service: %w", err)
+		}
+		if req.ProviderID != nil {
+			if err := s.bookingRepo.LockProvider(ctx, *req.ProviderID); err != nil {
+				return fmt.Errorf("failed to lock provider: %w", err)
+			}
+		}
+		if err := s.checkBookable(ctx, req); err != nil {
+			return err
+		}
+
+		if req.ProviderID != nil {
+			overlap, err := s.bookingRepo.HasOverlap(ctx, *req.ProviderID, req.StartsAt, req.EndsAt)
+			if err != nil {
+				return fmt.Errorf("failed to check provider schedule: %w", err)
+			}
+			if overlap {
+				return fmt.Errorf("%w: provider is already booked at that time", ErrConflict)
+			}
+		}
+
+		if err := s.bookingRepo.Create(ctx, req); err != nil {
+			return fmt.Errorf("failed to create booking: %w", err)
+		}
+		return s.bookingRepo.AddEvent(ctx, &models.BookingEvent{
+			BookingID: req.ID,
+			ToStatus:  req.Status,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *BookingService) GetBooking(ctx context.Context, id int64) (*models.Booking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if booking == nil {
+		return nil, fmt.Errorf("booking %w", ErrNotFound)
+	}
+	return booking, nil
+}
+
+func (s *BookingService) ListBookings(ctx context.Context, filter models.BookingFilter, opts models.ListOptions) (*models.Page[models.Booking], error) {
+	if err := normalizeListOptionsWith(&opts, models.SortStartsAt, models.SortID, models.SortCreated); err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && !isBookingStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown booking status %q", ErrInvalidListOptions, filter.Status)
+	}
+
+	page, err := s.bookingRepo.GetAll(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", listError(err))
+	}
+	return page, nil
+}
+
+// TransitionBooking moves a booking to a new status if the state machine
+// allows it, stamping the status timestamp and recording the change.
+func (s *BookingService) TransitionBooking(ctx context.Context, id int64, to, note string) (*models.Booking, error) {
+	if !isBookingStatus(to) {
+		return nil, fmt.Errorf("unknown booking status %q", to)
+	}
+
+	var booking *models.Booking
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		booking, err = s.bookingRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get booking: %w", err)
+		}
+		if booking == nil {
+			return fmt.Errorf("booking %w", ErrNotFound)
+		}
+
+		from := booking.Status
+		if !slices.Contains(bookingTransitions[from], to) {
+			return fmt.Errorf("%w: cannot move booking from %s to %s", ErrConflict, from, to)
+		}
+
+		booking.Status = to
+		if err := s.bookingRepo.UpdateStatus(ctx, booking); err != nil {
+			return fmt.Errorf("failed to update booking: %w", err)
+		}
+		return s.bookingRepo.AddEvent(ctx, &models.BookingEvent{
+			BookingID:  booking.ID,
+			FromStatus: &from,
+			ToStatus:   to,
+			Note:       note,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (s *BookingService) ListBookingEvents(ctx context.Context, id int64) ([]models.BookingEvent, error) {
+	if _, err := s.GetBooking(ctx, id); err != nil {
+		return nil, err
+	}
+
+	events, err := s.bookingRepo.GetEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list booking events: %w", err)
+	}
+	return events, nil
+}
+
+// checkBookable rejects bookings for inactive services, services in inactive
+// categories and providers that do not perform the service.
+func (s *BookingService) checkBookable(ctx context.Context, req *models.Booking) error {
+	service, err := s.serviceRepo.GetByID(ctx, req.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return errors.New("service not found")
+	}
+	if !service.IsActive {
+		return errors.New("service is not active")
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, service.CategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil || !category.IsActive {
+		return errors.New("service category is not active")
+	}
+
+	if req.ProviderID == nil {
+		return nil
+	}
+	offers, err := s.providerRepo.GetByService(ctx, req.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service providers: %w", err)
+	}
+	for _, offer := range offers {
+		if offer.ID == *req.ProviderID {
+			if !offer.IsActive {
+				return errors.New("provider is not active")
+			}
+			return nil
+		}
+	}
+	return errors.New("provider does not offer this service")
+}
+
+func isBookingStatus(status string) bool {
+	switch status {
+	case models.BookingRequested, models.BookingConfirmed, models.BookingInProgress,
+		models.BookingCompleted, models.BookingCancelled:
+		return true
+	}
+	return false
+}
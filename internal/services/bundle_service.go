@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// maxBundleName bounds the length of bundle names.
+const maxBundleName = 255
+
+// maxBundleQuantity bounds how many times a bundle includes one service.
+const maxBundleQuantity = 100
+
+type BundleService struct {
+	tx           repositories.Transactor
+	bundleRepo   repositories.BundleRepo
+	serviceRepo  repositories.ServiceRepo
+	categoryRepo repositories.CategoryRepo
+}
+
+func NewBundleService(
+	tx repositories.Transactor,
+	bundleRepo repositories.BundleRepo,
+	serviceRepo repositories.ServiceRepo,
+	categoryRepo repositories.CategoryRepo,
+) *BundleService {
+	return &BundleService{
+		tx:           tx,
+		bundleRepo:   bundleRepo,
+		serviceRepo:  serviceRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreateBundle adds a bundle of services. An active bundle may only contain
+// active services.
+func (s *BundleService) CreateBundle(ctx context.Context, req *models.Bundle) (*models.Bundle, error) {
+	if err := s.checkBundle(ctx, req, ""); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.bundleRepo.Create(ctx, req); err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+		if err := s.bundleRepo.SetItems(ctx, req.ID, req.Items); err != nil {
+			return fmt.Errorf("failed to save bundle items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBundle(ctx, req.ID)
+}
+
+// GetBundle returns a bundle with its services and prices.
+func (s *BundleService) GetBundle(ctx context.Context, id int64) (*models.Bundle, error) {
+	bundle, err := s.bundleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle: %w", err)
+	}
+	if bundle == nil {
+		return nil, fmt.Errorf("bundle %w", ErrNotFound)
+	}
+	if err := priceBundles(ctx, s.bundleRepo, []*models.Bundle{bundle}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (s *BundleService) ListBundles(ctx context.Context, opts models.ListOptions) (*models.Page[models.Bundle], error) {
+	if err := normalizeListOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	page, err := s.bundleRepo.GetAll(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bundles: %w", listError(err))
+	}
+	if err := priceBundles(ctx, s.bundleRepo, pointers(page.Items)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListBundlesByCategory lists the bundles of a category, optionally
+// including those of every category nested below it.
+func (s *BundleService) ListBundlesByCategory(ctx context.Context, categoryID int64, includeDescendants bool, isActive *bool) ([]models.Bundle, error) {
+	bundles, err := s.bundleRepo.GetByCategory(ctx, categoryID, includeDescendants, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bundles by category: %w", err)
+	}
+	if err := priceBundles(ctx, s.bundleRepo, pointers(bundles)); err != nil {
+		return nil, err
+	}
+	return bundles, nil
+}
+
+// UpdateBundle replaces a bundle and its services.
+func (s *BundleService) UpdateBundle(ctx context.Context, req *models.Bundle) (*models.Bundle, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.bundleRepo.GetByID(ctx, req.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get bundle: %w", err)
+		}
+		if current == nil {
+			return fmt.Errorf("bundle %w", ErrNotFound)
+		}
+		reason := ""
+		if !current.IsActive {
+			reason = current.InactiveReason
+		}
+		if err := s.checkBundle(ctx, req, reason); err != nil {
+			return err
+		}
+
+		if err := s.bundleRepo.Update(ctx, req); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("bundle %w", ErrNotFound)
+			}
+			return fmt.Errorf("failed to update bundle: %w", err)
+		}
+		if err := s.bundleRepo.SetItems(ctx, req.ID, req.Items); err != nil {
+			return fmt.Errorf("failed to save bundle items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetBundle(ctx, req.ID)
+}
+
+// DeleteBundle removes a bundle. Its services are not affected.
+func (s *BundleService) DeleteBundle(ctx context.Context, id int64) error {
+	if err := s.bundleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("bundle %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete bundle: %w", err)
+	}
+	return nil
+}
+
+// priceBundles adds the services of bundles and works out their prices from
+// the current service prices.
+func priceBundles(ctx context.Context, repo repositories.BundleRepo, bundles []*models.Bundle) error {
+	if len(bundles) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(bundles))
+	for i, b := range bundles {
+		ids[i] = b.ID
+	}
+	items, err := repo.GetItems(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get bundle items: %w", err)
+	}
+
+	byBundle := make(map[int64][]models.BundleItem, len(bundles))
+	for _, item := range items {
+		byBundle[item.BundleID] = append(byBundle[item.BundleID], item)
+	}
+	for _, b := range bundles {
+		b.Items = byBundle[b.ID]
+		if b.Items == nil {
+			b.Items = []models.BundleItem{}
+		}
+		b.ListPrice = 0
+		for _, item := range b.Items {
+			b.ListPrice += item.UnitPrice * int64(item.Quantity)
+			b.Currency = item.Currency
+		}
+		if b.PriceRule == models.BundlePriceDiscount {
+			b.Price = b.ListPrice - percentOf(b.ListPrice, int64(b.DiscountBP))
+		}
+	}
+	return nil
+}
+
+// checkBundle validates a bundle about to be saved, normalizing its items
+// and price rule. reason is kept as the inactive reason of a bundle that
+// stays inactive.
+func (s *BundleService) checkBundle(ctx context.Context, b *models.Bundle, reason string) error {
+	b.Name = strings.TrimSpace(b.Name)
+	if b.Name == "" {
+		return errors.New("bundle name cannot be empty")
+	}
+	if utf8.RuneCountInString(b.Name) > maxBundleName {
+		return fmt.Errorf("bundle name cannot be longer than %d characters", maxBundleName)
+	}
+	if b.CategoryID == 0 {
+		return errors.New("category ID is required")
+	}
+	category, err := s.categoryRepo.GetByID(ctx, b.CategoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category: %w", err)
+	}
+	if category == nil {
+		return errors.New("category does not exist")
+	}
+
+	switch b.PriceRule {
+	case "":
+		b.PriceRule = models.BundlePriceFixed
+		fallthrough
+	case models.BundlePriceFixed:
+		if b.Price < 0 {
+			return errors.New("price cannot be negative")
+		}
+		b.DiscountBP = 0
+	case models.BundlePriceDiscount:
+		if b.DiscountBP < 0 || b.DiscountBP > basisPoints {
+			return fmt.Errorf("discount_bp must be between 0 and %d", basisPoints)
+		}
+		b.Price = 0
+	default:
+		return fmt.Errorf("price rule must be %q or %q", models.BundlePriceFixed, models.BundlePriceDiscount)
+	}
+
+	if b.IsActive {
+		reason = ""
+	}
+	b.InactiveReason = reason
+	return s.checkItems(ctx, b)
+}
+
+// checkItems verifies that the services of a bundle exist, share a currency
+// and, for an active bundle, are active themselves.
+func (s *BundleService) checkItems(ctx context.Context, b *models.Bundle) error {
+	if len(b.Items) == 0 {
+		return errors.New("bundle needs at least one service")
+	}
+
+	seen := make(map[int64]bool, len(b.Items))
+	currency := ""
+	for i := range b.Items {
+		item := &b.Items[i]
+		if seen[item.ServiceID] {
+			return fmt.Errorf("service %d is listed more than once", item.ServiceID)
+		}
+		seen[item.ServiceID] = true
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity < 0 || item.Quantity > maxBundleQuantity {
+			return fmt.Errorf("quantity of service %d must be between 1 and %d", item.ServiceID, maxBundleQuantity)
+		}
+
+		service, err := s.serviceRepo.GetByID(ctx, item.ServiceID)
+		if err != nil {
+			return fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return fmt.Errorf("service %d does not exist", item.ServiceID)
+		}
+		if b.IsActive && !service.IsActive {
+			return fmt.Errorf("service %q is not active; an active bundle needs active services", service.Name)
+		}
+		if currency != "" && service.Currency != currency {
+			return fmt.Errorf("service %q is priced in %s, not %s like the rest of the bundle",
+				service.Name, service.Currency, currency)
+		}
+		currency = service.Currency
+	}
+	return nil
+}
+
+// deactivateBundles switches off the bundles containing a service that was
+// just deactivated or deleted, as told by what.
+func deactivateBundles(ctx context.Context, repo repositories.BundleRepo, service *models.Service, what string) error {
+	reason := fmt.Sprintf("service %q was %s", service.Name, what)
+	if err := repo.DeactivateContaining(ctx, service.ID, reason); err != nil {
+		return fmt.Errorf("failed to deactivate bundles: %w", err)
+	}
+	return nil
+}
@@ -1,39 +1,90 @@
 package services
 
 import (
+	"server/internal/audit"
 	"server/internal/models"
+	"server/internal/patch"
 	"server/internal/repositories"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 )
 
 type CategoryService struct {
-	repo repositories.CategoryRepo
+	tx              repositories.Transactor
+	repo            repositories.CategoryRepo
+	mediaRepo       repositories.MediaRepo
+	translationRepo repositories.TranslationRepo
+	auditRepo       repositories.AuditRepo
+	slugRepo        repositories.SlugRepo
+	revisionRepo    repositories.RevisionRepo
+	serviceRepo     repositories.ServiceRepo
+	bundleRepo      repositories.BundleRepo
 }
 
-func NewCategoryService(repo repositories.CategoryRepo) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(
+	tx repositories.Transactor,
+	repo repositories.CategoryRepo,
+	mediaRepo repositories.MediaRepo,
+	translationRepo repositories.TranslationRepo,
+	auditRepo repositories.AuditRepo,
+	slugRepo repositories.SlugRepo,
+	revisionRepo repositories.RevisionRepo,
+	serviceRepo repositories.ServiceRepo,
+	bundleRepo repositories.BundleRepo,
+) *CategoryService {
+	return &CategoryService{
+		tx:              tx,
+		repo:            repo,
+		mediaRepo:       mediaRepo,
+		translationRepo: translationRepo,
+		auditRepo:       auditRepo,
+		slugRepo:        slugRepo,
+		revisionRepo:    revisionRepo,
+		serviceRepo:     serviceRepo,
+		bundleRepo:      bundleRepo,
+	}
 }
 
+// CreateCategory adds a category, live straight away, and records it as its
+// first published revision.
 func (s *CategoryService) CreateCategory(ctx context.Context, req *models.Category) (*models.Category, error) {
 	if req.Name == "" {
 		return nil, errors.New("category name cannot be empty")
 	}
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt, nil, nil); err != nil {
+		return nil, err
+	}
 
-	existing, err := s.repo.GetAll(ctx)
+	existing, err := s.repo.GetByName(ctx, req.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify category: %w", err)
 	}
+	if existing != nil {
+		return nil, errors.New("category name already exists")
+	}
 
-	for _, c := range existing {
-		if c.Name == req.Name {
-			return nil, errors.New("category name already exists")
+	if req.ParentID != nil {
+		if err := s.checkParent(ctx, req.ParentID); err != nil {
+			return nil, err
 		}
 	}
 
-	if err := s.repo.Create(ctx, req); err != nil {
-		return nil, fmt.Errorf("failed to create category: %w", err)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.setSlug(ctx, req, ""); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, req); err != nil {
+			return fmt.Errorf("failed to create category: %w", err)
+		}
+		if err := s.audit(ctx, models.AuditCreate, req.ID, nil, s.repo.GetByID); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityCategory, req.ID, req, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return req, nil
@@ -44,35 +95,731 @@ func (s *CategoryService) GetCategory(ctx context.Context, id int64) (*models.Ca
 	if err != nil {
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
+	if category != nil {
+		if err := s.loadDetails(ctx, category); err != nil {
+			return nil, err
+		}
+	}
 	return category, nil
 }
 
-func (s *CategoryService) ListCategories(ctx context.Context) ([]models.Category, error) {
-	categories, err := s.repo.GetAll(ctx)
+// GetCategoryBySlug returns the category with the given slug. A slug the
+// category no longer has fails with a MovedError naming its current one.
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	category, err := s.repo.GetBySlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list categories: %w", err)
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, movedError(ctx, s.slugRepo, models.EntityCategory, slug)
+	}
+	if err := s.loadDetails(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// loadDetails adds the media and translations to a category being served.
+func (s *CategoryService) loadDetails(ctx context.Context, category *models.Category) error {
+	var err error
+	if category.Media, err = loadMedia(ctx, s.mediaRepo.GetByCategory, category.ID); err != nil {
+		return err
 	}
-	return categories, nil
+	return localizeCategories(ctx, s.translationRepo, []*models.Category{category})
 }
 
+func (s *CategoryService) ListCategories(ctx context.Context, opts models.ListOptions) (*models.Page[models.Category], error) {
+	if err := normalizeCatalogListOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	page, err := s.repo.GetAll(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", listError(err))
+	}
+	if err := localizeCategories(ctx, s.translationRepo, pointers(page.Items)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// UpdateCategory saves a category straight away, bypassing its draft, and
+// records it as the published revision.
 func (s *CategoryService) UpdateCategory(ctx context.Context, req *models.Category) error {
 	if req.ID == 0 {
 		return errors.New("invalid category ID")
 	}
-	
-	if err := s.repo.Update(ctx, req); err != nil {
-		return fmt.Errorf("failed to update category: %w", err)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Version, err = matchVersion(before.Version, req.Version); err != nil {
+			return err
+		}
+		if err := ensurePublished(ctx, s.revisionRepo, models.EntityCategory, req.ID, before); err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditUpdate, before, req, nil); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityCategory, req.ID, req, nil)
+	})
+}
+
+// SaveCategoryDraft validates req and saves it as the draft revision of the
+// category, replacing any draft it had. The live category is left as it is
+// until the draft is published. req must be based on the live category at
+// req.Version, or any version when that is 0.
+func (s *CategoryService) SaveCategoryDraft(ctx context.Context, req *models.Category) (*models.Revision, error) {
+	if req.ID == 0 {
+		return nil, errors.New("invalid category ID")
+	}
+	if err := s.checkUpdate(ctx, req); err != nil {
+		return nil, err
 	}
-	return nil
+
+	var draft *models.Revision
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		live, err := s.getLive(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Version, err = matchVersion(live.Version, req.Version); err != nil {
+			return err
+		}
+		if err := validateSchedule(req.PublishAt, req.UnpublishAt, live.PublishAt, live.UnpublishAt); err != nil {
+			return err
+		}
+		if err := s.setSlug(ctx, req, live.Slug); err != nil {
+			return err
+		}
+		draft, err = saveDraft(ctx, s.revisionRepo, models.EntityCategory, req.ID, live, req, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// PatchCategoryDraft applies a merge patch or JSON patch to the draft of a
+// category, or to the live category when it has no draft, and saves the
+// result as its draft. version is checked against the live category, 0
+// matching any version. It returns the category as the draft would make it.
+func (s *CategoryService) PatchCategoryDraft(ctx context.Context, id, version int64, p patch.Patch) (*models.Category, error) {
+	var patched *models.Category
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		live, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := matchVersion(live.Version, version); err != nil {
+			return err
+		}
+		current, base, err := s.withDraft(ctx, live)
+		if err != nil {
+			return err
+		}
+
+		if patched, err = applyPatch(current, p); err != nil {
+			return err
+		}
+		if err := s.checkUpdate(ctx, patched); err != nil {
+			return err
+		}
+		if err := validateSchedule(patched.PublishAt, patched.UnpublishAt, live.PublishAt, live.UnpublishAt); err != nil {
+			return err
+		}
+		if patched.Slug != live.Slug {
+			if err := checkSlug(ctx, s.slugRepo, models.EntityCategory, id, patched.Slug); err != nil {
+				return err
+			}
+		}
+		fields, err := changedFields(current, patched, categoryPatchFields)
+		if err != nil || len(fields) == 0 {
+			return err
+		}
+
+		_, err = saveDraft(ctx, s.revisionRepo, models.EntityCategory, id, live, patched, base)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// ApplyCategoryDraft returns a copy of category, as served, showing the
+// content of its draft.
+func (s *CategoryService) ApplyCategoryDraft(ctx context.Context, category *models.Category) (*models.Category, error) {
+	draft, err := findRevision(ctx, s.revisionRepo, models.EntityCategory, category.ID, 0, models.RevisionDraft)
+	if err != nil {
+		return nil, err
+	}
+	return applyRevision(category, draft.Content)
+}
+
+// PublishCategory makes the draft of a category live and its published
+// revision.
+func (s *CategoryService) PublishCategory(ctx context.Context, id int64) (*models.Category, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		draft, err := s.revisionRepo.GetByStatus(ctx, models.EntityCategory, id, models.RevisionDraft)
+		if err != nil {
+			return fmt.Errorf("failed to get draft: %w", err)
+		}
+		if draft == nil {
+			return fmt.Errorf("%w: category has no draft to publish", ErrConflict)
+		}
+		edits, err := draftEdits(models.EntityCategory, draft, before)
+		if err != nil {
+			return err
+		}
+
+		req, err := applyRevision(before, edits)
+		if err != nil {
+			return err
+		}
+		columns, err := contentChanges(models.EntityCategory, before, req)
+		if err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditPublish, before, req, columns); err != nil {
+			return err
+		}
+		if err := s.revisionRepo.Publish(ctx, draft, audit.Actor(ctx)); err != nil {
+			return fmt.Errorf("failed to publish draft: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCategory(ctx, id)
+}
+
+// RollbackCategory makes an earlier revision of a category live again,
+// recording it as a new published revision. The schedule of the category
+// and any pending draft are left as they are.
+func (s *CategoryService) RollbackCategory(ctx context.Context, id int64, number int) (*models.Category, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		revision, err := findRevision(ctx, s.revisionRepo, models.EntityCategory, id, number, "")
+		if err != nil {
+			return err
+		}
+		if revision.Status == models.RevisionDraft {
+			return fmt.Errorf("%w: revision %d is the draft; publish it instead", ErrConflict, number)
+		}
+
+		req, err := applyRevision(before, revision.Content)
+		if err != nil {
+			return err
+		}
+		req.PublishAt, req.UnpublishAt = before.PublishAt, before.UnpublishAt
+		columns, err := contentChanges(models.EntityCategory, before, req)
+		if err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditPublish, before, req, columns); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityCategory, id, req, &number)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCategory(ctx, id)
+}
+
+// ListCategoryRevisions returns the revisions of a category, newest first.
+func (s *CategoryService) ListCategoryRevisions(ctx context.Context, id int64) ([]models.Revision, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.GetByEntity(ctx, models.EntityCategory, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// DiffCategoryRevisions compares two revisions of a category, by default
+// the published one and the draft.
+func (s *CategoryService) DiffCategoryRevisions(ctx context.Context, id int64, from, to int) (*models.RevisionDiff, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	return diffRevisions(ctx, s.revisionRepo, models.EntityCategory, id, from, to)
+}
+
+// DependentsError is returned when a category cannot be deleted because
+// live subcategories, services or bundles still belong to it.
+type DependentsError struct {
+	Subcategories []models.Category
+	Services      []models.Service
+	Bundles       []models.Bundle
+}
+
+func (e *DependentsError) Error() string {
+	return fmt.Sprintf("category still has %d subcategories, %d services and %d bundles",
+		len(e.Subcategories), len(e.Services), len(e.Bundles))
+}
+
+// empty reports whether nothing belongs to the category.
+func (e *DependentsError) empty() bool {
+	return len(e.Subcategories) == 0 && len(e.Services) == 0 && len(e.Bundles) == 0
+}
+
+// Unwrap makes a DependentsError a conflict.
+func (e *DependentsError) Unwrap() error {
+	return ErrConflict
 }
 
-func (s *CategoryService) DeleteCategory(ctx context.Context, id int64) error {
+// DeleteCategory moves a category to the trash if it is at version, or at any
+// version when version is 0. Its live subcategories, services and bundles
+// are moved to another category or removed along with it as opts says, all
+// in one transaction; without either option they make it fail with a
+// DependentsError listing them.
+func (s *CategoryService) DeleteCategory(ctx context.Context, id, version int64, opts models.CategoryDeleteOptions) error {
 	if id == 0 {
 		return errors.New("invalid category ID")
 	}
-	
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
+	if opts.ReassignTo != nil && opts.Cascade {
+		return errors.New("reassign_to and cascade cannot be combined")
+	}
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		if version, err = matchVersion(before.Version, version); err != nil {
+			return err
+		}
+
+		dependents, err := s.dependents(ctx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case opts.ReassignTo != nil:
+			err = s.reassignDependents(ctx, id, *opts.ReassignTo, dependents)
+		case opts.Cascade:
+			err = s.trashDependents(ctx, id)
+		case !dependents.empty():
+			err = dependents
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("failed to delete category: %w", staleError(err))
+		}
+		return s.audit(ctx, models.AuditDelete, id, before, s.repo.GetTrashed)
+	})
+}
+
+// dependents returns the live child categories, services and bundles of a
+// category.
+func (s *CategoryService) dependents(ctx context.Context, id int64) (*DependentsError, error) {
+	children, err := s.repo.GetChildren(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category contents: %w", err)
+	}
+	services, err := s.serviceRepo.GetByCategory(ctx, id, false, models.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category contents: %w", err)
+	}
+	bundles, err := s.bundleRepo.GetByCategory(ctx, id, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check category contents: %w", err)
+	}
+	return &DependentsError{Subcategories: children, Services: services, Bundles: bundles}, nil
+}
+
+// reassignDependents moves the children, services and bundles of a category
+// about to be deleted to the category targetID, which must not lie inside
+// it.
+func (s *CategoryService) reassignDependents(ctx context.Context, id, targetID int64, dependents *DependentsError) error {
+	target, err := s.repo.GetByID(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to verify reassign_to category: %w", err)
+	}
+	if target == nil {
+		return errors.New("reassign_to category not found")
+	}
+	inside, err := s.repo.IsDescendant(ctx, id, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to verify reassign_to category: %w", err)
+	}
+	if inside {
+		return errors.New("reassign_to cannot be the category being deleted or one of its descendants")
+	}
+
+	if err := s.repo.MoveChildren(ctx, id, targetID); err != nil {
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+	if err := s.serviceRepo.MoveToCategory(ctx, id, targetID); err != nil {
+		return fmt.Errorf("failed to move services: %w", err)
+	}
+	if err := s.bundleRepo.MoveToCategory(ctx, id, targetID); err != nil {
+		return fmt.Errorf("failed to move bundles: %w", err)
+	}
+	children, services := dependents.Subcategories, dependents.Services
+	for i := range children {
+		if err := s.audit(ctx, models.AuditUpdate, children[i].ID, &children[i], s.repo.GetByID); err != nil {
+			return err
+		}
+	}
+	for i := range services {
+		if err := s.auditService(ctx, models.AuditUpdate, &services[i], s.serviceRepo.GetByID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashDependents moves every category nested below a category about to be
+// deleted to the trash, along with the services of all of them and of the
+// category itself. The bundles of all of them are deleted, since bundles
+// have no trash and would keep the categories from being purged; other
+// bundles containing the services are deactivated.
+func (s *CategoryService) trashDependents(ctx context.Context, id int64) error {
+	subtree, err := s.repo.GetSubtree(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get category tree: %w", err)
+	}
+	services, err := s.serviceRepo.GetByCategory(ctx, id, true, models.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	bundles, err := s.bundleRepo.GetByCategory(ctx, id, true, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list bundles: %w", err)
+	}
+
+	for _, bundle := range bundles {
+		if err := s.bundleRepo.Delete(ctx, bundle.ID); err != nil {
+			return fmt.Errorf("failed to delete bundle %q: %w", bundle.Name, err)
+		}
+	}
+
+	for i := range services {
+		service := &services[i]
+		if err := s.serviceRepo.Delete(ctx, service.ID, service.Version); err != nil {
+			return fmt.Errorf("failed to delete service %q: %w", service.Name, staleError(err))
+		}
+		if err := deactivateBundles(ctx, s.bundleRepo, service, "deleted"); err != nil {
+			return err
+		}
+		if err := s.auditService(ctx, models.AuditDelete, service, s.serviceRepo.GetTrashed); err != nil {
+			return err
+		}
+	}
+	// The subtree comes parents first and starts with the category itself,
+	// which the caller deletes; the rest go leaves first.
+	for i := len(subtree) - 1; i > 0; i-- {
+		category := &subtree[i]
+		if err := s.repo.Delete(ctx, category.ID, category.Version); err != nil {
+			return fmt.Errorf("failed to delete category %q: %w", category.Name, staleError(err))
+		}
+		if err := s.audit(ctx, models.AuditDelete, category.ID, category, s.repo.GetTrashed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReorderCategories sets the display order of the children of a category,
+// or of the top-level categories when parentID is nil, to that of ids, which
+// must list each of them exactly once.
+func (s *CategoryService) ReorderCategories(ctx context.Context, parentID *int64, ids []int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var categories []models.Category
+		var err error
+		if parentID == nil {
+			categories, err = s.repo.GetRoots(ctx)
+		} else {
+			if _, err := s.getLive(ctx, *parentID); err != nil {
+				return err
+			}
+			categories, err = s.repo.GetChildren(ctx, *parentID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list categories: %w", err)
+		}
+		current := make([]int64, len(categories))
+		byID := make(map[int64]*models.Category, len(categories))
+		for i := range categories {
+			current[i] = categories[i].ID
+			byID[categories[i].ID] = &categories[i]
+		}
+		if err := checkReorder(ids, current); err != nil {
+			return err
+		}
+
+		if err := s.repo.Reorder(ctx, ids); err != nil {
+			return fmt.Errorf("failed to reorder categories: %w", err)
+		}
+		for i, id := range ids {
+			if before := byID[id]; before.SortOrder != i+1 {
+				if err := s.audit(ctx, models.AuditUpdate, id, before, s.repo.GetByID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreCategory takes a category back out of the trash. Its parent, if
+// any, must not be in the trash itself.
+func (s *CategoryService) RestoreCategory(ctx context.Context, id int64) (*models.Category, error) {
+	category, err := s.repo.GetTrashed(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("deleted category %w", ErrNotFound)
+	}
+
+	if category.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *category.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify parent category: %w", err)
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("%w: parent category %d is deleted; restore it first", ErrConflict, *category.ParentID)
+		}
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Restore(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrDuplicate) {
+				return fmt.Errorf("%w: another category is already named %q", ErrConflict, category.Name)
+			}
+			return fmt.Errorf("failed to restore category: %w", err)
+		}
+		return s.audit(ctx, models.AuditRestore, id, category, s.repo.GetByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return restored, nil
+}
+
+func (s *CategoryService) GetCategoryAncestors(ctx context.Context, id int64) ([]models.Category, error) {
+	if _, err := s.mustGet(ctx, id); err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.repo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category ancestors: %w", err)
+	}
+	if err := localizeCategories(ctx, s.translationRepo, pointers(ancestors)); err != nil {
+		return nil, err
+	}
+	return ancestors, nil
+}
+
+func (s *CategoryService) ListChildCategories(ctx context.Context, id int64) ([]models.Category, error) {
+	if _, err := s.mustGet(ctx, id); err != nil {
+		return nil, err
+	}
+
+	children, err := s.repo.GetChildren(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child categories: %w", err)
+	}
+	if err := localizeCategories(ctx, s.translationRepo, pointers(children)); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// GetCategoryTree returns a category with all of its descendants nested
+// under it.
+func (s *CategoryService) GetCategoryTree(ctx context.Context, id int64) (*models.CategoryNode, error) {
+	categories, err := s.repo.GetSubtree(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category tree: %w", err)
+	}
+	if len(categories) == 0 {
+		return nil, errors.New("category not found")
+	}
+	if err := localizeCategories(ctx, s.translationRepo, pointers(categories)); err != nil {
+		return nil, err
+	}
+
+	// The subtree comes back parents-first, so every parent node already
+	// exists by the time its children are attached.
+	nodes := make(map[int64]*models.CategoryNode, len(categories))
+	root := &models.CategoryNode{Category: categories[0], Children: []*models.CategoryNode{}}
+	nodes[root.ID] = root
+	for _, c := range categories[1:] {
+		node := &models.CategoryNode{Category: c, Children: []*models.CategoryNode{}}
+		nodes[c.ID] = node
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return root, nil
+}
+
+// save writes req over before, the live category, within the transaction
+// carried by ctx and audits it as action. Given columns, only those are
+// written, and nothing when there are none.
+func (s *CategoryService) save(ctx context.Context, action string, before, req *models.Category, columns []string) error {
+	if err := s.checkUpdate(ctx, req); err != nil {
+		return err
+	}
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt, before.PublishAt, before.UnpublishAt); err != nil {
+		return err
+	}
+	if err := s.setSlug(ctx, req, before.Slug); err != nil {
+		return err
+	}
+	var err error
+	switch {
+	case columns == nil:
+		err = s.repo.Update(ctx, req)
+	case len(columns) > 0:
+		err = s.repo.Patch(ctx, req, columns)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update category: %w", staleError(err))
+	}
+	return s.audit(ctx, action, req.ID, before, s.repo.GetByID)
+}
+
+// getLive returns the stored category, failing with ErrNotFound when there
+// is none.
+func (s *CategoryService) getLive(ctx context.Context, id int64) (*models.Category, error) {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("category %w", ErrNotFound)
+	}
+	return category, nil
+}
+
+// withDraft returns live with the content of its draft, if it has one, and
+// the content the draft was made from, or nil when there is no draft.
+func (s *CategoryService) withDraft(ctx context.Context, live *models.Category) (*models.Category, json.RawMessage, error) {
+	draft, err := getDraft(ctx, s.revisionRepo, models.EntityCategory, live.ID)
+	if err != nil || draft == nil {
+		return live, nil, err
+	}
+	applied, err := applyRevision(live, draft.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if draft.BaseContent == nil {
+		return applied, nil, nil
+	}
+	return applied, *draft.BaseContent, nil
+}
+
+// audit records a change to a category, reading its new state with load
+// inside the transaction carried by ctx.
+func (s *CategoryService) audit(ctx context.Context, action string, id int64, before *models.Category,
+	load func(context.Context, int64) (*models.Category, error)) error {
+	after, err := load(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+	return recordAudit(ctx, s.auditRepo, action, models.EntityCategory, id, before, after)
+}
+
+// auditService records a change to a service this service makes, reading its
+// new state with load.
+func (s *CategoryService) auditService(ctx context.Context, action string, before *models.Service,
+	load func(context.Context, int64) (*models.Service, error)) error {
+	after, err := load(ctx, before.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+	return recordAudit(ctx, s.auditRepo, action, models.EntityService, before.ID, before, after)
+}
+
+// setSlug fills in the slug of a category being saved: a new one made from
+// the name if none is given, or the current one on update. A slug given
+// explicitly is checked.
+func (s *CategoryService) setSlug(ctx context.Context, category *models.Category, current string) error {
+	var err error
+	switch {
+	case category.Slug == "" && current != "":
+		category.Slug = current
+	case category.Slug == "":
+		category.Slug, err = newSlug(ctx, s.slugRepo, models.EntityCategory, category.Name)
+	case category.Slug != current:
+		err = checkSlug(ctx, s.slugRepo, models.EntityCategory, category.ID, category.Slug)
+	}
+	return err
+}
+
+// checkUpdate validates a category about to replace its stored version.
+func (s *CategoryService) checkUpdate(ctx context.Context, req *models.Category) error {
+	if req.Name == "" {
+		return errors.New("category name cannot be empty")
+	}
+	if req.ParentID == nil {
+		return nil
+	}
+
+	if err := s.checkParent(ctx, req.ParentID); err != nil {
+		return err
+	}
+	// Moving a category under itself or one of its descendants would
+	// detach that branch from the tree in a loop.
+	cycle, err := s.repo.IsDescendant(ctx, req.ID, *req.ParentID)
+	if err != nil {
+		return fmt.Errorf("failed to verify parent category: %w", err)
+	}
+	if cycle {
+		return errors.New("parent category cannot be the category itself or one of its descendants")
+	}
+	return nil
+}
+
+func (s *CategoryService) checkParent(ctx context.Context, parentID *int64) error {
+	parent, err := s.repo.GetByID(ctx, *parentID)
+	if err != nil {
+		return fmt.Errorf("failed to verify parent category: %w", err)
+	}
+	if parent == nil {
+		return errors.New("parent category not found")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *CategoryService) mustGet(ctx context.Context, id int64) (*models.Category, error) {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	if category == nil {
+		return nil, errors.New("category not found")
+	}
+	return category, nil
+}
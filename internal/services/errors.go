@@ -0,0 +1,18 @@
+package services
+
+import "errors"
+
+// Sentinel errors wrapped by service methods so handlers can choose a status
+// code without matching on messages.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrConflict  = errors.New("conflict")
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrPreconditionFailed is returned when a write names a version of an
+	// entity that is no longer current.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	ErrTooLarge             = errors.New("too large")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"server/internal/models"
+	"server/internal/xlsx"
+)
+
+// exportColumns are the columns of CSV and XLSX exports, named as the import
+// reads them.
+var exportColumns = []string{
+	"type", "name", "description", "is_active", "parent", "category", "duration_minutes",
+	"price", "currency", "pricing_unit", "min_price", "max_price",
+}
+
+// exportCells returns the values of row in the order of exportColumns, nil
+// for unset fields.
+func exportCells(row *models.ImportRow) []any {
+	cells := []any{row.Type, row.Name, nil, nil, row.Parent, row.Category, nil, nil,
+		row.Currency, row.PricingUnit, nil, nil}
+	if row.Description != nil {
+		cells[2] = *row.Description
+	}
+	if row.IsActive != nil {
+		cells[3] = *row.IsActive
+	}
+	if row.DurationMinutes != nil {
+		cells[6] = *row.DurationMinutes
+	}
+	if row.Price != nil {
+		cells[7] = *row.Price
+	}
+	if row.MinPrice != nil {
+		cells[10] = *row.MinPrice
+	}
+	if row.MaxPrice != nil {
+		cells[11] = *row.MaxPrice
+	}
+	for _, i := range []int{4, 5, 8, 9} {
+		if cells[i] == "" {
+			cells[i] = nil
+		}
+	}
+	return cells
+}
+
+// exportWriter writes export rows in one format. Close flushes what is
+// buffered and ends the file.
+type exportWriter interface {
+	Write(row *models.ImportRow) error
+	Close() error
+}
+
+func newExportWriter(w io.Writer, format string) (exportWriter, error) {
+	switch format {
+	case models.FormatCSV:
+		cw := csv.NewWriter(w)
+		return &csvExportWriter{w: cw}, cw.Write(exportColumns)
+	case models.FormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonExportWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case models.FormatXLSX:
+		xw, err := xlsx.NewWriter(w, "Catalog")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExportWriter{w: xw}, xw.WriteRow(stringCells(exportColumns)...)
+	}
+	return nil, fmt.Errorf("export format must be %q, %q or %q",
+		models.FormatCSV, models.FormatNDJSON, models.FormatXLSX)
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvExportWriter) Write(row *models.ImportRow) error {
+	cells := exportCells(row)
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvExportWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type ndjsonExportWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *ndjsonExportWriter) Write(row *models.ImportRow) error {
+	return w.enc.Encode(row)
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return w.w.Flush()
+}
+
+type xlsxExportWriter struct {
+	w *xlsx.Writer
+}
+
+func (w *xlsxExportWriter) Write(row *models.ImportRow) error {
+	return w.w.WriteRow(exportCells(row)...)
+}
+
+func (w *xlsxExportWriter) Close() error {
+	return w.w.Close()
+}
+
+func stringCells(values []string) []any {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
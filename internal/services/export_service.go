@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"server/internal/models"
+	"server/internal/repositories"
+	"server/internal/xlsx"
+)
+
+// exportTypes are the content types and file extensions of export formats.
+var exportTypes = map[string]struct{ contentType, extension string }{
+	models.FormatCSV:    {"text/csv; charset=utf-8", "csv"},
+	models.FormatNDJSON: {"application/x-ndjson", "ndjson"},
+	models.FormatXLSX:   {xlsx.ContentType, "xlsx"},
+}
+
+// ExportService streams the catalog out in the row format imports read.
+type ExportService struct {
+	repo repositories.ExportRepo
+}
+
+func NewExportService(repo repositories.ExportRepo) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// ExportType returns the content type and file extension of an export
+// format.
+func (s *ExportService) ExportType(format string) (contentType, extension string, err error) {
+	t, ok := exportTypes[format]
+	if !ok {
+		return "", "", fmt.Errorf("%w: export format must be %q, %q or %q",
+			ErrInvalidListOptions, models.FormatCSV, models.FormatNDJSON, models.FormatXLSX)
+	}
+	return t.contentType, t.extension, nil
+}
+
+// Export writes the categories and then the services matching the filters of
+// opts to w, row by row as they are read, along with every category above
+// them so that the file imports cleanly. Pagination and sort options are
+// ignored. Nothing is written when format or opts are invalid; an error after
+// that leaves w with a truncated file.
+func (s *ExportService) Export(ctx context.Context, w io.Writer, format string, opts models.ListOptions) error {
+	if _, _, err := s.ExportType(format); err != nil {
+		return err
+	}
+	if opts.Near != nil {
+		if err := validateGeoPoint(*opts.Near); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidListOptions, err)
+		}
+	}
+	opts.PostalCode = normalizePostalCode(opts.PostalCode)
+	if err := normalizeTagFilter(&opts); err != nil {
+		return err
+	}
+
+	ew, err := newExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Export(ctx, opts, ew.Write); err != nil {
+		return fmt.Errorf("failed to export catalog: %w", err)
+	}
+	return ew.Close()
+}
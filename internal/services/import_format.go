@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"server/internal/models"
+)
+
+// maxImportRows bounds the number of rows in one import.
+const maxImportRows = 10000
+
+// importLine is a parsed row of an import, or the reason it could not be
+// parsed.
+type importLine struct {
+	line int
+	row  models.ImportRow
+	err  error
+}
+
+// parseImport reads the rows of an import in the given format. Problems with
+// single rows are recorded on them; only problems with the input as a whole
+// are returned as errors.
+func parseImport(r io.Reader, format string) ([]importLine, error) {
+	var lines []importLine
+	var err error
+	switch format {
+	case models.FormatCSV:
+		lines, err = parseImportCSV(r)
+	case models.FormatNDJSON:
+		lines, err = parseImportNDJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: import format must be %q or %q",
+			ErrUnsupportedMediaType, models.FormatCSV, models.FormatNDJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("import has no rows")
+	}
+	if len(lines) > maxImportRows {
+		return nil, fmt.Errorf("%w: imports are limited to %d rows", ErrTooLarge, maxImportRows)
+	}
+	for i := range lines {
+		normalizeImportRow(&lines[i].row)
+	}
+	return lines, nil
+}
+
+// importColumns sets the field of an import row named by a CSV column.
+var importColumns = map[string]func(row *models.ImportRow, v string) error{
+	"type":         func(row *models.ImportRow, v string) error { row.Type = v; return nil },
+	"name":         func(row *models.ImportRow, v string) error { row.Name = v; return nil },
+	"description":  func(row *models.ImportRow, v string) error { row.Description = &v; return nil },
+	"parent":       func(row *models.ImportRow, v string) error { row.Parent = v; return nil },
+	"category":     func(row *models.ImportRow, v string) error { row.Category = v; return nil },
+	"currency":     func(row *models.ImportRow, v string) error { row.Currency = v; return nil },
+	"pricing_unit": func(row *models.ImportRow, v string) error { row.PricingUnit = v; return nil },
+	"is_active": func(row *models.ImportRow, v string) error {
+		b, err := strconv.ParseBool(v)
+		row.IsActive = &b
+		return err
+	},
+	"duration_minutes": func(row *models.ImportRow, v string) error {
+		n, err := strconv.Atoi(v)
+		row.DurationMinutes = &n
+		return err
+	},
+	"price":     func(row *models.ImportRow, v string) error { return parseImportInt(&row.Price, v) },
+	"min_price": func(row *models.ImportRow, v string) error { return parseImportInt(&row.MinPrice, v) },
+	"max_price": func(row *models.ImportRow, v string) error { return parseImportInt(&row.MaxPrice, v) },
+}
+
+func parseImportInt(dst **int64, v string) error {
+	n, err := strconv.ParseInt(v, 10, 64)
+	*dst = &n
+	return err
+}
+
+// parseImportCSV reads CSV with a header row naming the columns. Empty cells
+// leave their field unset.
+func parseImportCSV(r io.Reader) ([]importLine, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("import has no rows")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := importColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		for _, seen := range columns[:i] {
+			if seen == name {
+				return nil, fmt.Errorf("duplicate CSV column %q", name)
+			}
+		}
+		columns[i] = name
+	}
+
+	var lines []importLine
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		l := importLine{err: err}
+		l.line, _ = cr.FieldPos(0)
+		if err == nil {
+			for i, v := range record {
+				if v = strings.TrimSpace(v); v == "" {
+					continue
+				}
+				if err := importColumns[columns[i]](&l.row, v); err != nil {
+					l.err = fmt.Errorf("invalid %s %q", columns[i], v)
+					break
+				}
+			}
+		}
+		lines = append(lines, l)
+		if len(lines) > maxImportRows {
+			break
+		}
+	}
+	return lines, nil
+}
+
+// parseImportNDJSON reads one JSON object per line, skipping blank lines.
+func parseImportNDJSON(r io.Reader) ([]importLine, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	var lines []importLine
+	for n := 1; sc.Scan(); n++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		l := importLine{line: n}
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&l.row); err != nil {
+			l.err = fmt.Errorf("invalid JSON: %w", err)
+		} else if dec.More() {
+			l.err = errors.New("invalid JSON: one object per line expected")
+		}
+		lines = append(lines, l)
+		if len(lines) > maxImportRows {
+			break
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("invalid NDJSON: %w", err)
+	}
+	return lines, nil
+}
+
+func normalizeImportRow(row *models.ImportRow) {
+	row.Type = strings.ToLower(strings.TrimSpace(row.Type))
+	row.Name = strings.TrimSpace(row.Name)
+	row.Parent = strings.TrimSpace(row.Parent)
+	row.Category = strings.TrimSpace(row.Category)
+	row.Currency = strings.ToUpper(strings.TrimSpace(row.Currency))
+	row.PricingUnit = strings.TrimSpace(row.PricingUnit)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// errRollback makes WithinTx discard an import's writes without it failing.
+var errRollback = errors.New("rollback")
+
+// ImportService loads categories and services in bulk. Rows are written
+// through CategoryService and ServiceService, so they are validated and
+// audited exactly as single writes are.
+type ImportService struct {
+	tx           repositories.Transactor
+	categoryRepo repositories.CategoryRepo
+	serviceRepo  repositories.ServiceRepo
+	categories   *CategoryService
+	services     *ServiceService
+}
+
+func NewImportService(
+	tx repositories.Transactor,
+	categoryRepo repositories.CategoryRepo,
+	serviceRepo repositories.ServiceRepo,
+	categories *CategoryService,
+	services *ServiceService,
+) *ImportService {
+	return &ImportService{
+		tx:           tx,
+		categoryRepo: categoryRepo,
+		serviceRepo:  serviceRepo,
+		categories:   categories,
+		services:     services,
+	}
+}
+
+// Import creates or updates the categories and services in r, matching
+// existing ones by name. Rows run in order, each in its own savepoint of a
+// single transaction, so a row may refer to categories created by earlier
+// rows. Nothing is kept on a dry run, or in atomic mode if any row fails.
+func (s *ImportService) Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportAtomic
+	}
+	if opts.Mode != models.ImportAtomic && opts.Mode != models.ImportBestEffort {
+		return nil, fmt.Errorf("mode must be %q or %q", models.ImportAtomic, models.ImportBestEffort)
+	}
+
+	lines, err := parseImport(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{
+		Mode:   opts.Mode,
+		DryRun: opts.DryRun,
+		Rows:   make([]models.ImportRowResult, 0, len(lines)),
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, l := range lines {
+			result := models.ImportRowResult{Line: l.line, Type: l.row.Type, Name: l.row.Name}
+			err := l.err
+			if err == nil {
+				err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+					var id int64
+					var err error
+					if result.Status, id, err = s.importRow(ctx, &l.row); err == nil {
+						result.ID = &id
+					}
+					return err
+				})
+			}
+			if err != nil {
+				result.Status = models.ImportFailed
+				result.ID = nil
+				result.Reason = err.Error()
+			}
+			report.Rows = append(report.Rows, result)
+			countImportRow(report, result.Status)
+		}
+
+		if opts.DryRun || (opts.Mode == models.ImportAtomic && report.Failed > 0) {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return nil, err
+	}
+
+	report.Committed = err == nil
+	if !report.Committed {
+		for i := range report.Rows {
+			if report.Rows[i].Status == models.ImportCreated {
+				report.Rows[i].ID = nil
+			}
+		}
+	}
+	return report, nil
+}
+
+func (s *ImportService) importRow(ctx context.Context, row *models.ImportRow) (string, int64, error) {
+	if row.Name == "" {
+		return "", 0, errors.New("name is required")
+	}
+	switch row.Type {
+	case models.EntityCategory:
+		return s.importCategory(ctx, row)
+	case models.EntityService:
+		return s.importService(ctx, row)
+	}
+	return "", 0, fmt.Errorf("type must be %q or %q", models.EntityCategory, models.EntityService)
+}
+
+func (s *ImportService) importCategory(ctx context.Context, row *models.ImportRow) (string, int64, error) {
+	var parentID *int64
+	if row.Parent != "" {
+		parent, err := s.categoryRepo.GetByName(ctx, row.Parent)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get parent category: %w", err)
+		}
+		if parent == nil {
+			return "", 0, fmt.Errorf("parent category %q not found", row.Parent)
+		}
+		parentID = &parent.ID
+	}
+
+	existing, err := s.categoryRepo.GetByName(ctx, row.Name)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get category: %w", err)
+	}
+	if existing == nil {
+		category := &models.Category{Name: row.Name, ParentID: parentID, IsActive: true}
+		applyCategoryRow(category, row)
+		if _, err := s.categories.CreateCategory(ctx, category); err != nil {
+			return "", 0, err
+		}
+		return models.ImportCreated, category.ID, nil
+	}
+
+	updated := *existing
+	if parentID != nil {
+		updated.ParentID = parentID
+	}
+	applyCategoryRow(&updated, row)
+	fields, err := changedFields(existing, &updated, categoryPatchFields)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(fields) == 0 {
+		return models.ImportSkipped, existing.ID, nil
+	}
+	if err := s.categories.UpdateCategory(ctx, &updated); err != nil {
+		return "", 0, err
+	}
+	return models.ImportUpdated, existing.ID, nil
+}
+
+func (s *ImportService) importService(ctx context.Context, row *models.ImportRow) (string, int64, error) {
+	var categoryID int64
+	if row.Category != "" {
+		category, err := s.categoryRepo.GetByName(ctx, row.Category)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return "", 0, fmt.Errorf("category %q not found", row.Category)
+		}
+		categoryID = category.ID
+	}
+
+	existing, err := s.serviceRepo.GetByName(ctx, row.Name)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get service: %w", err)
+	}
+	if existing == nil {
+		if categoryID == 0 {
+			return "", 0, errors.New("category is required for new services")
+		}
+		service := &models.Service{CategoryID: categoryID, Name: row.Name, IsActive: true}
+		applyServiceRow(service, row)
+		if _, err := s.services.CreateService(ctx, service); err != nil {
+			return "", 0, err
+		}
+		return models.ImportCreated, service.ID, nil
+	}
+
+	updated := *existing
+	if categoryID != 0 {
+		updated.CategoryID = categoryID
+	}
+	applyServiceRow(&updated, row)
+	fields, err := changedFields(existing, &updated, servicePatchFields)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(fields) == 0 {
+		return models.ImportSkipped, existing.ID, nil
+	}
+	if err := s.services.UpdateService(ctx, &updated); err != nil {
+		return "", 0, err
+	}
+	return models.ImportUpdated, existing.ID, nil
+}
+
+// applyCategoryRow copies the fields set in row onto category.
+func applyCategoryRow(category *models.Category, row *models.ImportRow) {
+	if row.Description != nil {
+		category.Description = *row.Description
+	}
+	if row.IsActive != nil {
+		category.IsActive = *row.IsActive
+	}
+}
+
+// applyServiceRow copies the fields set in row onto service.
+func applyServiceRow(service *models.Service, row *models.ImportRow) {
+	if row.Description != nil {
+		service.Description = *row.Description
+	}
+	if row.IsActive != nil {
+		service.IsActive = *row.IsActive
+	}
+	if row.DurationMinutes != nil {
+		service.DurationMinutes = *row.DurationMinutes
+	}
+	if row.Price != nil {
+		service.Price = *row.Price
+	}
+	if row.Currency != "" {
+		service.Currency = row.Currency
+	}
+	if row.PricingUnit != "" {
+		service.PricingUnit = row.PricingUnit
+	}
+	if row.MinPrice != nil {
+		service.MinPrice = row.MinPrice
+	}
+	if row.MaxPrice != nil {
+		service.MaxPrice = row.MaxPrice
+	}
+}
+
+func countImportRow(report *models.ImportReport, status string) {
+	switch status {
+	case models.ImportCreated:
+		report.Created++
+	case models.ImportUpdated:
+		report.Updated++
+	case models.ImportSkipped:
+		report.Skipped++
+	case models.ImportFailed:
+		report.Failed++
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ErrInvalidListOptions is returned when pagination, sort or filter
+// parameters are malformed.
+var ErrInvalidListOptions = errors.New("invalid list options")
+
+// normalizeListOptions fills in defaults and validates opts in place for
+// lists which sort by name, id or creation.
+func normalizeListOptions(opts *models.ListOptions) error {
+	return normalizeListOptionsWith(opts, models.SortName, models.SortID, models.SortCreated)
+}
+
+// normalizeCatalogListOptions is normalizeListOptions for the category and
+// service lists, which default to the manual display order.
+func normalizeCatalogListOptions(opts *models.ListOptions) error {
+	return normalizeListOptionsWith(opts, models.SortManual, models.SortName, models.SortID, models.SortCreated)
+}
+
+// normalizeListOptionsWith is normalizeListOptions for lists supporting other
+// sort keys. The first key is the default.
+func normalizeListOptionsWith(opts *models.ListOptions, sorts ...string) error {
+	switch {
+	case opts.Limit == 0:
+		opts.Limit = defaultListLimit
+	case opts.Limit < 0 || opts.Limit > maxListLimit:
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListOptions, maxListLimit)
+	}
+
+	if opts.Sort == "" {
+		opts.Sort = sorts[0]
+	}
+	if !slices.Contains(sorts, opts.Sort) {
+		return fmt.Errorf("%w: unknown sort key %q", ErrInvalidListOptions, opts.Sort)
+	}
+
+	switch opts.Order {
+	case "":
+		opts.Order = models.OrderAsc
+	case models.OrderAsc, models.OrderDesc:
+	default:
+		return fmt.Errorf("%w: order must be %q or %q", ErrInvalidListOptions, models.OrderAsc, models.OrderDesc)
+	}
+	return nil
+}
+
+// listError marks repository cursor failures as client errors.
+func listError(err error) error {
+	if errors.Is(err, repositories.ErrInvalidCursor) {
+		return fmt.Errorf("%w: %v", ErrInvalidListOptions, err)
+	}
+	return err
+}
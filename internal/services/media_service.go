@@ -0,0 +1,326 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"unicode/utf8"
+
+	"server/internal/imaging"
+	"server/internal/models"
+	"server/internal/repositories"
+	"server/internal/storage"
+)
+
+// thumbnailSize is the longest side of generated thumbnails, in pixels.
+const thumbnailSize = 320
+
+// maxAltTextLength bounds media alt text in characters.
+const maxAltTextLength = 255
+
+type MediaService struct {
+	tx             repositories.Transactor
+	mediaRepo      repositories.MediaRepo
+	serviceRepo    repositories.ServiceRepo
+	categoryRepo   repositories.CategoryRepo
+	store          storage.Storage
+	maxUploadBytes int64
+}
+
+func NewMediaService(
+	tx repositories.Transactor,
+	mediaRepo repositories.MediaRepo,
+	serviceRepo repositories.ServiceRepo,
+	categoryRepo repositories.CategoryRepo,
+	store storage.Storage,
+	maxUploadBytes int64,
+) *MediaService {
+	return &MediaService{
+		tx:             tx,
+		mediaRepo:      mediaRepo,
+		serviceRepo:    serviceRepo,
+		categoryRepo:   categoryRepo,
+		store:          store,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// MaxUploadBytes is the largest accepted upload.
+func (s *MediaService) MaxUploadBytes() int64 {
+	return s.maxUploadBytes
+}
+
+// UploadMedia stores an image for the service or category set on req. The
+// content type is taken from the bytes, not from the client. The first image
+// of an owner becomes its primary one.
+func (s *MediaService) UploadMedia(ctx context.Context, req *models.Media, file io.Reader) (*models.Media, error) {
+	prefix, err := s.checkOwner(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAltText(req.AltText); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxUploadBytes {
+		return nil, fmt.Errorf("%w: uploads are limited to %d bytes", ErrTooLarge, s.maxUploadBytes)
+	}
+
+	img, contentType, err := imaging.Decode(data)
+	switch {
+	case errors.Is(err, imaging.ErrTooManyPixels):
+		return nil, fmt.Errorf("%w: %v", ErrTooLarge, err)
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+	}
+
+	var thumb bytes.Buffer
+	thumbType := imaging.ThumbnailType(contentType)
+	if err := imaging.Encode(&thumb, imaging.Thumbnail(img, thumbnailSize), thumbType); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	req.StorageKey = prefix + name + extensions[contentType]
+	req.ThumbnailKey = prefix + name + "_thumb" + extensions[thumbType]
+	req.ContentType = contentType
+	req.SizeBytes = int64(len(data))
+	req.Width, req.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	if err := s.store.Put(ctx, req.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to store media: %w", err)
+	}
+	if err := s.store.Put(ctx, req.ThumbnailKey, &thumb); err != nil {
+		removeMediaBlobs(ctx, s.store, req)
+		return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.ownerMedia(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list media: %w", err)
+		}
+		req.Position = 0
+		for _, m := range existing {
+			req.Position = max(req.Position, m.Position+1)
+		}
+		if len(existing) == 0 {
+			req.IsPrimary = true
+		}
+
+		if req.IsPrimary {
+			if err := s.mediaRepo.ClearPrimary(ctx, req); err != nil {
+				return fmt.Errorf("failed to update primary media: %w", err)
+			}
+		}
+		if err := s.mediaRepo.Create(ctx, req); err != nil {
+			return fmt.Errorf("failed to create media: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		removeMediaBlobs(ctx, s.store, req)
+		return nil, err
+	}
+
+	setMediaURLs(req)
+	return req, nil
+}
+
+func (s *MediaService) GetMedia(ctx context.Context, id int64) (*models.Media, error) {
+	media, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+	if media == nil {
+		return nil, fmt.Errorf("media %w", ErrNotFound)
+	}
+	setMediaURLs(media)
+	return media, nil
+}
+
+// ListServiceMedia returns a service's images in display order.
+func (s *MediaService) ListServiceMedia(ctx context.Context, serviceID int64) ([]models.Media, error) {
+	if _, err := s.checkOwner(ctx, &models.Media{ServiceID: &serviceID}); err != nil {
+		return nil, err
+	}
+	return loadMedia(ctx, s.mediaRepo.GetByService, serviceID)
+}
+
+// ListCategoryMedia returns a category's images in display order.
+func (s *MediaService) ListCategoryMedia(ctx context.Context, categoryID int64) ([]models.Media, error) {
+	if _, err := s.checkOwner(ctx, &models.Media{CategoryID: &categoryID}); err != nil {
+		return nil, err
+	}
+	return loadMedia(ctx, s.mediaRepo.GetByCategory, categoryID)
+}
+
+// UpdateMedia changes the alt text, position and primary flag of an image.
+// Making an image primary demotes its owner's previous primary image.
+func (s *MediaService) UpdateMedia(ctx context.Context, id int64, altText string, position int, isPrimary bool) (*models.Media, error) {
+	if err := validateAltText(altText); err != nil {
+		return nil, err
+	}
+	if position < 0 {
+		return nil, errors.New("position cannot be negative")
+	}
+
+	var media *models.Media
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		media, err = s.GetMedia(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		media.AltText = altText
+		media.Position = position
+		media.IsPrimary = isPrimary
+		if isPrimary {
+			if err := s.mediaRepo.ClearPrimary(ctx, media); err != nil {
+				return fmt.Errorf("failed to update primary media: %w", err)
+			}
+		}
+		if err := s.mediaRepo.Update(ctx, media); err != nil {
+			return fmt.Errorf("failed to update media: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// DeleteMedia removes an image and its stored blobs.
+func (s *MediaService) DeleteMedia(ctx context.Context, id int64) error {
+	media, err := s.GetMedia(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.mediaRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete media: %w", err)
+	}
+	removeMediaBlobs(ctx, s.store, media)
+	return nil
+}
+
+// OpenMedia returns an image, or its thumbnail, for streaming to a client,
+// with the content type of the returned bytes.
+func (s *MediaService) OpenMedia(ctx context.Context, id int64, thumbnail bool) (io.ReadCloser, string, error) {
+	media, err := s.GetMedia(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, contentType := media.StorageKey, media.ContentType
+	if thumbnail {
+		key, contentType = media.ThumbnailKey, imaging.ThumbnailType(media.ContentType)
+	}
+	r, err := s.store.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, "", fmt.Errorf("media file %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open media: %w", err)
+	}
+	return r, contentType, nil
+}
+
+// checkOwner verifies that exactly one owner is set on media and exists,
+// returning the storage key prefix for its blobs.
+func (s *MediaService) checkOwner(ctx context.Context, media *models.Media) (string, error) {
+	switch {
+	case media.ServiceID != nil && media.CategoryID == nil:
+		service, err := s.serviceRepo.GetByID(ctx, *media.ServiceID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return "", fmt.Errorf("service %w", ErrNotFound)
+		}
+		return fmt.Sprintf("services/%d/", *media.ServiceID), nil
+	case media.CategoryID != nil && media.ServiceID == nil:
+		category, err := s.categoryRepo.GetByID(ctx, *media.CategoryID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return "", fmt.Errorf("category %w", ErrNotFound)
+		}
+		return fmt.Sprintf("categories/%d/", *media.CategoryID), nil
+	}
+	return "", errors.New("media must belong to exactly one service or category")
+}
+
+func (s *MediaService) ownerMedia(ctx context.Context, media *models.Media) ([]models.Media, error) {
+	if media.ServiceID != nil {
+		return s.mediaRepo.GetByService(ctx, *media.ServiceID)
+	}
+	return s.mediaRepo.GetByCategory(ctx, *media.CategoryID)
+}
+
+// removeMediaBlobs deletes the stored files of media. Failures only leave
+// orphaned files behind, so they are logged rather than returned.
+func removeMediaBlobs(ctx context.Context, store storage.Storage, media *models.Media) {
+	for _, key := range []string{media.StorageKey, media.ThumbnailKey} {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete media blob %s: %v", key, err)
+		}
+	}
+}
+
+// loadMedia fetches the images of one owner with their URLs set. It is shared
+// with the services embedding media in their responses.
+func loadMedia(ctx context.Context, get func(context.Context, int64) ([]models.Media, error), ownerID int64) ([]models.Media, error) {
+	media, err := get(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	if media == nil {
+		media = []models.Media{}
+	}
+	for i := range media {
+		setMediaURLs(&media[i])
+	}
+	return media, nil
+}
+
+func setMediaURLs(media *models.Media) {
+	media.URL = fmt.Sprintf("/media/%d/file", media.ID)
+	media.ThumbnailURL = fmt.Sprintf("/media/%d/thumbnail", media.ID)
+}
+
+// extensions maps the content types of stored blobs to file extensions.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func validateAltText(altText string) error {
+	if utf8.RuneCountInString(altText) > maxAltTextLength {
+		return fmt.Errorf("alt text cannot exceed %d characters", maxAltTextLength)
+	}
+	return nil
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate media name: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// maxOptionName bounds the names of option groups and options.
+const maxOptionName = 255
+
+// maxQuoteUnits bounds the units and option quantities of a quote.
+const maxQuoteUnits = 10000
+
+// basisPoints is 100%, in the unit percentage modifiers are given in.
+const basisPoints = 10000
+
+var optionGroupKinds = map[string]bool{
+	models.OptionGroupSingle:   true,
+	models.OptionGroupMulti:    true,
+	models.OptionGroupQuantity: true,
+}
+
+type OptionService struct {
+	tx          repositories.Transactor
+	optionRepo  repositories.OptionRepo
+	serviceRepo repositories.ServiceRepo
+}
+
+func NewOptionService(
+	tx repositories.Transactor,
+	optionRepo repositories.OptionRepo,
+	serviceRepo repositories.ServiceRepo,
+) *OptionService {
+	return &OptionService{tx: tx, optionRepo: optionRepo, serviceRepo: serviceRepo}
+}
+
+// ListOptionGroups returns the option groups of a service with their options.
+func (s *OptionService) ListOptionGroups(ctx context.Context, serviceID int64) ([]models.OptionGroup, error) {
+	if _, err := s.getService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	groups, err := s.optionRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list option groups: %w", err)
+	}
+	return groups, nil
+}
+
+// CreateOptionGroup adds an option group to a service, together with the
+// options listed in it.
+func (s *OptionService) CreateOptionGroup(ctx context.Context, serviceID int64, req *models.OptionGroup) (*models.OptionGroup, error) {
+	if _, err := s.getService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	req.ServiceID = serviceID
+	if err := validateOptionGroup(req); err != nil {
+		return nil, err
+	}
+	if req.Options == nil {
+		req.Options = []models.Option{}
+	}
+	for i := range req.Options {
+		if err := validateOption(&req.Options[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.optionRepo.CreateGroup(ctx, req); err != nil {
+			return fmt.Errorf("failed to create option group: %w", err)
+		}
+		for i := range req.Options {
+			req.Options[i].GroupID = req.ID
+			if err := s.optionRepo.CreateOption(ctx, &req.Options[i]); err != nil {
+				return fmt.Errorf("failed to create option: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// UpdateOptionGroup saves the settings of an option group. Its options are
+// changed through their own endpoints and are returned as they are.
+func (s *OptionService) UpdateOptionGroup(ctx context.Context, req *models.OptionGroup) (*models.OptionGroup, error) {
+	current, err := s.getGroup(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	req.ServiceID, req.CreatedAt = current.ServiceID, current.CreatedAt
+	if err := validateOptionGroup(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionRepo.UpdateGroup(ctx, req); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("option group %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update option group: %w", err)
+	}
+	if req.Options, err = s.optionRepo.GetOptions(ctx, req.ID); err != nil {
+		return nil, fmt.Errorf("failed to get options: %w", err)
+	}
+	return req, nil
+}
+
+// DeleteOptionGroup removes an option group with its options.
+func (s *OptionService) DeleteOptionGroup(ctx context.Context, id int64) error {
+	if err := s.optionRepo.DeleteGroup(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("option group %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete option group: %w", err)
+	}
+	return nil
+}
+
+// CreateOption adds an option to an option group.
+func (s *OptionService) CreateOption(ctx context.Context, groupID int64, req *models.Option) (*models.Option, error) {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return nil, err
+	}
+	req.GroupID = groupID
+	if err := validateOption(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionRepo.CreateOption(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to create option: %w", err)
+	}
+	return req, nil
+}
+
+// UpdateOption saves an option. It stays in its group.
+func (s *OptionService) UpdateOption(ctx context.Context, req *models.Option) (*models.Option, error) {
+	current, err := s.optionRepo.GetOption(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option: %w", err)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("option %w", ErrNotFound)
+	}
+	req.GroupID, req.CreatedAt = current.GroupID, current.CreatedAt
+	if err := validateOption(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionRepo.UpdateOption(ctx, req); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("option %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update option: %w", err)
+	}
+	return req, nil
+}
+
+func (s *OptionService) DeleteOption(ctx context.Context, id int64) error {
+	if err := s.optionRepo.DeleteOption(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("option %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete option: %w", err)
+	}
+	return nil
+}
+
+// Quote checks a configuration of a service against its option groups and
+// prices it: the base price times the units, plus a line per selected
+// option. Percentage modifiers apply to the base price. The total is never
+// negative and is kept within the service's minimum and maximum price.
+func (s *OptionService) Quote(ctx context.Context, serviceID int64, req *models.QuoteRequest) (*models.Quote, error) {
+	service, err := s.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !service.IsActive {
+		return nil, errors.New("service is not active")
+	}
+
+	units := req.Units
+	switch {
+	case service.PricingUnit == models.PricingUnitFlat && units > 1:
+		return nil, errors.New("units cannot be given for a service priced flat")
+	case units == 0:
+		units = 1
+	case units < 0 || units > maxQuoteUnits:
+		return nil, fmt.Errorf("units must be between 1 and %d", maxQuoteUnits)
+	}
+
+	groups, err := s.optionRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option groups: %w", err)
+	}
+	selected, err := quoteSelections(groups, req.Selections)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &models.Quote{
+		ServiceID:   service.ID,
+		Currency:    service.Currency,
+		PricingUnit: service.PricingUnit,
+		Units:       units,
+		BasePrice:   service.Price * int64(units),
+		Lines:       []models.QuoteLine{},
+	}
+	quote.Subtotal = quote.BasePrice
+	for _, group := range groups {
+		for _, option := range group.Options {
+			quantity, ok := selected[option.ID]
+			if !ok {
+				continue
+			}
+			unit := option.ModifierAmount
+			if option.ModifierType == models.ModifierPercent {
+				unit = percentOf(quote.BasePrice, option.ModifierAmount)
+			}
+			line := models.QuoteLine{
+				GroupID:    group.ID,
+				GroupName:  group.Name,
+				OptionID:   option.ID,
+				OptionName: option.Name,
+				Quantity:   quantity,
+				UnitAmount: unit,
+				Amount:     unit * int64(quantity),
+			}
+			quote.Lines = append(quote.Lines, line)
+			quote.Subtotal += line.Amount
+		}
+	}
+
+	quote.Total = max(quote.Subtotal, 0)
+	quote.Adjustment = quote.Total - quote.Subtotal
+	return quote, nil
+}
+
+// quoteSelections checks the selections of a quote against the option
+// groups of the service, returning the quantity of each selected option.
+func quoteSelections(groups []models.OptionGroup, selections []models.OptionSelection) (map[int64]int, error) {
+	owner := make(map[int64]*models.OptionGroup)
+	for i := range groups {
+		for _, option := range groups[i].Options {
+			owner[option.ID] = &groups[i]
+		}
+	}
+
+	selected := make(map[int64]int, len(selections))
+	counts := make(map[int64]int)
+	for _, sel := range selections {
+		group, ok := owner[sel.OptionID]
+		if !ok {
+			return nil, fmt.Errorf("option %d is not an option of this service", sel.OptionID)
+		}
+		if _, ok := selected[sel.OptionID]; ok {
+			return nil, fmt.Errorf("option %d is selected more than once", sel.OptionID)
+		}
+
+		quantity := sel.Quantity
+		switch {
+		case quantity == 0:
+			quantity = 1
+		case group.Kind != models.OptionGroupQuantity && quantity != 1:
+			return nil, fmt.Errorf("option %d cannot be given a quantity: %q is not a quantity group", sel.OptionID, group.Name)
+		case quantity < 0 || quantity > maxQuoteUnits:
+			return nil, fmt.Errorf("quantity of option %d must be between 1 and %d", sel.OptionID, maxQuoteUnits)
+		}
+		selected[sel.OptionID] = quantity
+		if group.Kind == models.OptionGroupQuantity {
+			counts[group.ID] += quantity
+		} else {
+			counts[group.ID]++
+		}
+	}
+
+	for _, group := range groups {
+		count := counts[group.ID]
+		if count == 0 && !group.Required {
+			continue
+		}
+		what := "options"
+		if group.Kind == models.OptionGroupQuantity {
+			what = "in total"
+		}
+		if count < max(group.MinSelect, 1) {
+			if count == 0 {
+				return nil, fmt.Errorf("%q is required", group.Name)
+			}
+			return nil, fmt.Errorf("%q needs at least %d %s", group.Name, group.MinSelect, what)
+		}
+		if group.MaxSelect != nil && count > *group.MaxSelect {
+			return nil, fmt.Errorf("%q allows at most %d %s", group.Name, *group.MaxSelect, what)
+		}
+	}
+	return selected, nil
+}
+
+// percentOf returns bp basis points of amount, rounded half away from zero.
+func percentOf(amount, bp int64) int64 {
+	n := amount * bp
+	if n < 0 {
+		return (n - basisPoints/2) / basisPoints
+	}
+	return (n + basisPoints/2) / basisPoints
+}
+
+func (s *OptionService) getService(ctx context.Context, serviceID int64) (*models.Service, error) {
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+	return service, nil
+}
+
+func (s *OptionService) getGroup(ctx context.Context, id int64) (*models.OptionGroup, error) {
+	group, err := s.optionRepo.GetGroup(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option group: %w", err)
+	}
+	if group == nil {
+		return nil, fmt.Errorf("option group %w", ErrNotFound)
+	}
+	return group, nil
+}
+
+// validateOptionGroup checks an option group about to be saved. A single
+// group allows one option, and a required group at least one.
+func validateOptionGroup(group *models.OptionGroup) error {
+	if err := validateOptionName(&group.Name, "option group"); err != nil {
+		return err
+	}
+	if !optionGroupKinds[group.Kind] {
+		return fmt.Errorf("option group kind must be %q, %q or %q",
+			models.OptionGroupSingle, models.OptionGroupMulti, models.OptionGroupQuantity)
+	}
+
+	if group.MinSelect < 0 {
+		return errors.New("min_select cannot be negative")
+	}
+	if group.Kind == models.OptionGroupSingle {
+		if group.MinSelect > 1 || (group.MaxSelect != nil && *group.MaxSelect != 1) {
+			return errors.New("a single option group allows exactly one option")
+		}
+		one := 1
+		group.MaxSelect = &one
+	}
+	if group.Required && group.MinSelect == 0 {
+		group.MinSelect = 1
+	}
+	if group.MaxSelect != nil {
+		if *group.MaxSelect < 1 {
+			return errors.New("max_select must be at least 1")
+		}
+		if group.MinSelect > *group.MaxSelect {
+			return errors.New("min_select cannot exceed max_select")
+		}
+	}
+	return nil
+}
+
+// validateOption checks an option about to be saved. A percentage discount
+// cannot take off more than the whole base price.
+func validateOption(option *models.Option) error {
+	if err := validateOptionName(&option.Name, "option"); err != nil {
+		return err
+	}
+	switch option.ModifierType {
+	case "":
+		option.ModifierType = models.ModifierFixed
+	case models.ModifierFixed:
+	case models.ModifierPercent:
+		if option.ModifierAmount < -basisPoints {
+			return errors.New("a percentage discount cannot exceed 100% (-10000 basis points)")
+		}
+	default:
+		return fmt.Errorf("modifier type must be %q or %q", models.ModifierFixed, models.ModifierPercent)
+	}
+	return nil
+}
+
+func validateOptionName(name *string, what string) error {
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return fmt.Errorf("%s name cannot be empty", what)
+	}
+	if utf8.RuneCountInString(*name) > maxOptionName {
+		return fmt.Errorf("%s name cannot be longer than %d characters", what, maxOptionName)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"server/internal/audit"
+	"server/internal/patch"
+)
+
+// Fields of each entity that a patch may change. Everything else is
+// read-only.
+var (
+	categoryPatchFields = []string{"parent_id", "name", "slug", "description", "is_active", "publish_at", "unpublish_at"}
+	servicePatchFields  = []string{
+		"category_id", "name", "slug", "description", "is_active", "publish_at", "unpublish_at", "duration_minutes",
+		"price", "currency", "pricing_unit", "min_price", "max_price",
+	}
+)
+
+// applyPatch applies p to the JSON form of current and decodes the result
+// as a new T. Members T does not have are rejected.
+func applyPatch[T any](current *T, p patch.Patch) (*T, error) {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	if doc, err = p.Apply(doc); err != nil {
+		if errors.Is(err, patch.ErrTestFailed) {
+			return nil, fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	var patched T
+	if err := dec.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("invalid patched document: %w", err)
+	}
+	return &patched, nil
+}
+
+// changedFields returns the fields that differ between before and after,
+// failing if any of them is not writable.
+func changedFields(before, after any, writable []string) ([]string, error) {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(changes))
+	for name := range changes {
+		if !slices.Contains(writable, name) {
+			return nil, fmt.Errorf("field %q is read-only", name)
+		}
+		fields = append(fields, name)
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"server/internal/models"
+)
+
+// iso4217 lists the active ISO 4217 currency codes accepted for prices.
+var iso4217 = map[string]bool{
+	"AED": true, "AFN": true, "ALL": true, "AMD": true, "ANG": true, "AOA": true, "ARS": true, "AUD": true,
+	"AWG": true, "AZN": true, "BAM": true, "BBD": true, "BDT": true, "BGN": true, "BHD": true, "BIF": true,
+	"BMD": true, "BND": true, "BOB": true, "BRL": true, "BSD": true, "BTN": true, "BWP": true, "BYN": true,
+	"BZD": true, "CAD": true, "CDF": true, "CHF": true, "CLP": true, "CNY": true, "COP": true, "CRC": true,
+	"CUP": true, "CVE": true, "CZK": true, "DJF": true, "DKK": true, "DOP": true, "DZD": true, "EGP": true,
+	"ERN": true, "ETB": true, "EUR": true, "FJD": true, "FKP": true, "GBP": true, "GEL": true, "GHS": true,
+	"GIP": true, "GMD": true, "GNF": true, "GTQ": true, "GYD": true, "HKD": true, "HNL": true, "HTG": true,
+	"HUF": true, "IDR": true, "ILS": true, "INR": true, "IQD": true, "IRR": true, "ISK": true, "JMD": true,
+	"JOD": true, "JPY": true, "KES": true, "KGS": true, "KHR": true, "KMF": true, "KPW": true, "KRW": true,
+	"KWD": true, "KYD": true, "KZT": true, "LAK": true, "LBP": true, "LKR": true, "LRD": true, "LSL": true,
+	"LYD": true, "MAD": true, "MDL": true, "MGA": true, "MKD": true, "MMK": true, "MNT": true, "MOP": true,
+	"MRU": true, "MUR": true, "MVR": true, "MWK": true, "MXN": true, "MYR": true, "MZN": true, "NAD": true,
+	"NGN": true, "NIO": true, "NOK": true, "NPR": true, "NZD": true, "OMR": true, "PAB": true, "PEN": true,
+	"PGK": true, "PHP": true, "PKR": true, "PLN": true, "PYG": true, "QAR": true, "RON": true, "RSD": true,
+	"RUB": true, "RWF": true, "SAR": true, "SBD": true, "SCR": true, "SDG": true, "SEK": true, "SGD": true,
+	"SHP": true, "SLE": true, "SOS": true, "SRD": true, "SSP": true, "STN": true, "SVC": true, "SYP": true,
+	"SZL": true, "THB": true, "TJS": true, "TMT": true, "TND": true, "TOP": true, "TRY": true, "TTD": true,
+	"TWD": true, "TZS": true, "UAH": true, "UGX": true, "USD": true, "UYU": true, "UZS": true, "VES": true,
+	"VND": true, "VUV": true, "WST": true, "XAF": true, "XCD": true, "XOF": true, "XPF": true, "YER": true,
+	"ZAR": true, "ZMW": true, "ZWL": true,
+}
+
+var pricingUnits = map[string]bool{
+	models.PricingUnitFlat:       true,
+	models.PricingUnitPerHour:    true,
+	models.PricingUnitPerSqMetre: true,
+	models.PricingUnitPerItem:    true,
+}
+
+// defaultCurrency is the currency of services created without one, matching
+// the column default.
+const defaultCurrency = "USD"
+
+// validatePricing normalizes the currency code and checks the pricing fields
+// of a service. A service saved without a currency keeps current, its stored
+// currency, or gets defaultCurrency when it has none yet.
+func validatePricing(req *models.Service, current string) error {
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.Currency == "" {
+		req.Currency = current
+	}
+	if req.Currency == "" {
+		req.Currency = defaultCurrency
+	}
+	if !iso4217[req.Currency] {
+		return fmt.Errorf("unsupported currency %q", req.Currency)
+	}
+
+	if req.PricingUnit == "" {
+		req.PricingUnit = models.PricingUnitFlat
+	}
+	if !pricingUnits[req.PricingUnit] {
+		return fmt.Errorf("unsupported pricing unit %q", req.PricingUnit)
+	}
+
+	if req.Price < 0 {
+		return errors.New("price cannot be negative")
+	}
+	if req.MinPrice != nil && *req.MinPrice < 0 {
+		return errors.New("minimum price cannot be negative")
+	}
+	if req.MaxPrice != nil && *req.MaxPrice < 0 {
+		return errors.New("maximum price cannot be negative")
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return errors.New("minimum price cannot exceed maximum price")
+	}
+	if req.MinPrice != nil && req.Price < *req.MinPrice {
+		return errors.New("price cannot be below the minimum price")
+	}
+	if req.MaxPrice != nil && req.Price > *req.MaxPrice {
+		return errors.New("price cannot exceed the maximum price")
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+type ProviderService struct {
+	providerRepo repositories.ProviderRepo
+	serviceRepo  repositories.ServiceRepo
+}
+
+func NewProviderService(
+	providerRepo repositories.ProviderRepo,
+	serviceRepo repositories.ServiceRepo,
+) *ProviderService {
+	return &ProviderService{
+		providerRepo: providerRepo,
+		serviceRepo:  serviceRepo,
+	}
+}
+
+func (s *ProviderService) CreateProvider(ctx context.Context, req *models.Provider) (*models.Provider, error) {
+	if err := validateProvider(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.providerRepo.Create(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to create provider: %w", duplicateEmailError(err, req.Email))
+	}
+	return req, nil
+}
+
+func (s *ProviderService) GetProvider(ctx context.Context, id int64) (*models.Provider, error) {
+	provider, err := s.providerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+	if provider == nil {
+		return nil, errors.New("provider not found")
+	}
+	return provider, nil
+}
+
+func (s *ProviderService) ListProviders(ctx context.Context, opts models.ListOptions) (*models.Page[models.Provider], error) {
+	if err := normalizeListOptions(&opts); err != nil {
+		return nil, err
+	}
+
+	page, err := s.providerRepo.GetAll(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", listError(err))
+	}
+	return page, nil
+}
+
+func (s *ProviderService) UpdateProvider(ctx context.Context, req *models.Provider) error {
+	if req.ID == 0 {
+		return errors.New("invalid provider ID")
+	}
+	if err := validateProvider(req); err != nil {
+		return err
+	}
+
+	if err := s.providerRepo.Update(ctx, req); err != nil {
+		return fmt.Errorf("failed to update provider: %w", duplicateEmailError(err, req.Email))
+	}
+	return nil
+}
+
+func (s *ProviderService) DeleteProvider(ctx context.Context, id int64) error {
+	if id == 0 {
+		return errors.New("invalid provider ID")
+	}
+
+	if err := s.providerRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete provider: %w", err)
+	}
+	return nil
+}
+
+// AttachService records that a provider performs a service, optionally at a
+// provider-specific price in the service's currency.
+func (s *ProviderService) AttachService(ctx context.Context, providerID, serviceID int64, priceOverride *int64) error {
+	if _, err := s.GetProvider(ctx, providerID); err != nil {
+		return err
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return errors.New("service not found")
+	}
+
+	if priceOverride != nil && *priceOverride < 0 {
+		return errors.New("price override cannot be negative")
+	}
+
+	if err := s.providerRepo.AttachService(ctx, providerID, serviceID, priceOverride); err != nil {
+		return fmt.Errorf("failed to attach service: %w", err)
+	}
+	return nil
+}
+
+func (s *ProviderService) DetachService(ctx context.Context, providerID, serviceID int64) error {
+	if err := s.providerRepo.DetachService(ctx, providerID, serviceID); err != nil {
+		return fmt.Errorf("failed to detach service: %w", err)
+	}
+	return nil
+}
+
+func (s *ProviderService) ListProviderServices(ctx context.Context, providerID int64) ([]models.ServiceOffer, error) {
+	if _, err := s.GetProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	offers, err := s.providerRepo.GetServices(ctx, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider services: %w", err)
+	}
+	return offers, nil
+}
+
+func (s *ProviderService) ListServiceProviders(ctx context.Context, serviceID int64) ([]models.ProviderOffer, error) {
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+
+	offers, err := s.providerRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service providers: %w", err)
+	}
+	return offers, nil
+}
+
+// duplicateEmailError turns the duplicate record error of a provider saved
+// with an email another provider has into a conflict.
+func duplicateEmailError(err error, email string) error {
+	if errors.Is(err, repositories.ErrDuplicate) {
+		return fmt.Errorf("%w: another provider already uses email %q", ErrConflict, email)
+	}
+	return err
+}
+
+func validateProvider(req *models.Provider) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("provider name cannot be empty")
+	}
+
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		return fmt.Errorf("invalid provider email: %w", err)
+	}
+	req.Email = addr.Address
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return fmt.Errorf("invalid provider timezone %q", req.Timezone)
+	}
+	return nil
+}
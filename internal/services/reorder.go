@@ -0,0 +1,41 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+)
+
+// checkReorder verifies that ids names each of the current ids exactly once,
+// so a reorder rewrites every position. A list naming other items was built
+// from a stale view and conflicts with the current state.
+func checkReorder(ids, current []int64) error {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Errorf("id %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	var missing, unknown []int64
+	for _, id := range current {
+		if !seen[id] {
+			missing = append(missing, id)
+		}
+		delete(seen, id)
+	}
+	for id := range seen {
+		unknown = append(unknown, id)
+	}
+	slices.Sort(unknown)
+
+	switch {
+	case len(missing) > 0 && len(unknown) > 0:
+		return fmt.Errorf("%w: ids %v are missing and %v are unknown", ErrConflict, missing, unknown)
+	case len(missing) > 0:
+		return fmt.Errorf("%w: ids %v are missing", ErrConflict, missing)
+	case len(unknown) > 0:
+		return fmt.Errorf("%w: ids %v are unknown", ErrConflict, unknown)
+	}
+	return nil
+}
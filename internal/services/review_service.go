@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// reviewEditWindow is how long after posting a customer may still edit a
+// review.
+const reviewEditWindow = 7 * 24 * time.Hour
+
+// maxReviewLength bounds the text of a review in bytes.
+const maxReviewLength = 5000
+
+type ReviewService struct {
+	tx          repositories.Transactor
+	reviewRepo  repositories.ReviewRepo
+	serviceRepo repositories.ServiceRepo
+}
+
+func NewReviewService(
+	tx repositories.Transactor,
+	reviewRepo repositories.ReviewRepo,
+	serviceRepo repositories.ServiceRepo,
+) *ReviewService {
+	return &ReviewService{
+		tx:          tx,
+		reviewRepo:  reviewRepo,
+		serviceRepo: serviceRepo,
+	}
+}
+
+// CreateReview posts a review for moderation. Each customer may review a
+// service once. The review is returned with the token its author needs to
+// edit it, which cannot be retrieved later.
+func (s *ReviewService) CreateReview(ctx context.Context, req *models.Review) (*models.Review, error) {
+	service, err := s.serviceRepo.GetByID(ctx, req.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+
+	email, err := normalizeEmail(req.CustomerEmail)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customer email: %w", err)
+	}
+	req.CustomerEmail = email
+	req.CustomerName = strings.TrimSpace(req.CustomerName)
+	if err := validateReviewContent(req); err != nil {
+		return nil, err
+	}
+
+	req.Status = models.ReviewPending
+	req.ModerationNote = ""
+	req.HelpfulCount = 0
+	if req.EditToken, req.EditTokenHash, err = newEditToken(); err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.Create(ctx, req); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: customer has already reviewed this service", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+	return req, nil
+}
+
+func (s *ReviewService) GetReview(ctx context.Context, id int64) (*models.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		return nil, fmt.Errorf("review %w", ErrNotFound)
+	}
+	return review, nil
+}
+
+// ListReviews lists reviews, newest first unless asked otherwise. Public
+// callers should filter on approved reviews.
+func (s *ReviewService) ListReviews(ctx context.Context, filter models.ReviewFilter, opts models.ListOptions) (*models.Page[models.Review], error) {
+	if opts.Order == "" {
+		opts.Order = models.OrderDesc
+	}
+	if err := normalizeListOptionsWith(&opts, models.SortRecent, models.SortHelpful); err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && !isReviewStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown review status %q", ErrInvalidListOptions, filter.Status)
+	}
+
+	page, err := s.reviewRepo.GetAll(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", listError(err))
+	}
+	return page, nil
+}
+
+// EditReview lets the author, holding the edit token the review was posted
+// with, change its rating and text within the edit window. The edited review
+// goes back to moderation.
+func (s *ReviewService) EditReview(ctx context.Context, id int64, editToken string, rating int, body string) (*models.Review, error) {
+	var review *models.Review
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.reviewRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get review: %w", err)
+		}
+		if review == nil {
+			return fmt.Errorf("review %w", ErrNotFound)
+		}
+		if !checkEditToken(editToken, review.EditTokenHash) {
+			return fmt.Errorf("%w: only the author can edit a review", ErrForbidden)
+		}
+		if time.Since(review.CreatedAt) > reviewEditWindow {
+			return fmt.Errorf("%w: reviews can only be edited within %s of posting", ErrConflict, reviewEditWindow)
+		}
+
+		old := *review
+		review.Rating = rating
+		review.Body = body
+		review.Status = models.ReviewPending
+		review.ModerationNote = ""
+		if err := validateReviewContent(review); err != nil {
+			return err
+		}
+		return s.save(ctx, &old, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// ModerateReview approves or rejects a review, updating the service's rating
+// aggregates to match.
+func (s *ReviewService) ModerateReview(ctx context.Context, id int64, status, note string) (*models.Review, error) {
+	if !isReviewStatus(status) {
+		return nil, fmt.Errorf("unknown review status %q", status)
+	}
+
+	var review *models.Review
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = s.reviewRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get review: %w", err)
+		}
+		if review == nil {
+			return fmt.Errorf("review %w", ErrNotFound)
+		}
+
+		old := *review
+		review.Status = status
+		review.ModerationNote = strings.TrimSpace(note)
+		return s.save(ctx, &old, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// MarkHelpful records that a voter found a review helpful. Repeat votes by
+// the same voter are ignored.
+func (s *ReviewService) MarkHelpful(ctx context.Context, id int64, voterEmail string) error {
+	email, err := normalizeEmail(voterEmail)
+	if err != nil {
+		return fmt.Errorf("invalid voter email: %w", err)
+	}
+
+	review, err := s.GetReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	if review.Status != models.ReviewApproved {
+		return fmt.Errorf("review %w", ErrNotFound)
+	}
+
+	if _, err := s.reviewRepo.AddHelpfulVote(ctx, id, email); err != nil {
+		return fmt.Errorf("failed to record vote: %w", err)
+	}
+	return nil
+}
+
+// save writes a changed review and moves the service's aggregates by the
+// difference between the old and new review's contribution, so they never
+// need recomputing from scratch.
+func (s *ReviewService) save(ctx context.Context, old, updated *models.Review) error {
+	if err := s.reviewRepo.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+
+	oldCount, oldTotal := ratingContribution(old)
+	newCount, newTotal := ratingContribution(updated)
+	if oldCount == newCount && oldTotal == newTotal {
+		return nil
+	}
+	if err := s.reviewRepo.AdjustServiceRating(ctx, updated.ServiceID, newCount-oldCount, newTotal-oldTotal); err != nil {
+		return fmt.Errorf("failed to update service rating: %w", err)
+	}
+	return nil
+}
+
+// newEditToken returns a random token for editing a review and the hash of
+// it that is stored.
+func newEditToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate edit token: %w", err)
+	}
+	token = hex.EncodeToString(b)
+	return token, hashEditToken(token), nil
+}
+
+func hashEditToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkEditToken reports whether token is the one hash was made from.
+func checkEditToken(token, hash string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(hashEditToken(token)), []byte(hash)) == 1
+}
+
+// ratingContribution is what a review adds to its service's review count
+// and rating total.
+func ratingContribution(r *models.Review) (count, total int) {
+	if r.Status != models.ReviewApproved {
+		return 0, 0
+	}
+	return 1, r.Rating
+}
+
+func validateReviewContent(r *models.Review) error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+	r.Body = strings.TrimSpace(r.Body)
+	if len(r.Body) > maxReviewLength {
+		return fmt.Errorf("review text cannot exceed %d characters", maxReviewLength)
+	}
+	return nil
+}
+
+func isReviewStatus(status string) bool {
+	switch status {
+	case models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+		return true
+	}
+	return false
+}
+
+// normalizeEmail parses an address and lower-cases it so the same customer
+// is recognized however they type it.
+func normalizeEmail(email string) (string, error) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(addr.Address), nil
+}
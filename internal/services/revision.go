@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"server/internal/audit"
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// revisionFields are the fields of each entity its revisions keep: the ones
+// an edit may change.
+var revisionFields = map[string][]string{
+	models.EntityCategory: categoryPatchFields,
+	models.EntityService:  servicePatchFields,
+}
+
+// revisionContent returns the revision content of an entity of entityType:
+// the JSON form of its writable fields, with those it leaves out as null.
+func revisionContent(entityType string, entity any) (json.RawMessage, error) {
+	all, err := jsonFields(entity)
+	if err != nil {
+		return nil, err
+	}
+	fields := revisionFields[entityType]
+	content := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if v, ok := all[name]; ok {
+			content[name] = v
+		} else {
+			content[name] = json.RawMessage("null")
+		}
+	}
+	return json.Marshal(content)
+}
+
+// applyRevision returns a copy of entity with its writable fields set from
+// the content of a revision.
+func applyRevision[T any](entity *T, content json.RawMessage) (*T, error) {
+	fields, err := jsonFields(entity)
+	if err != nil {
+		return nil, err
+	}
+	var revised map[string]json.RawMessage
+	if err := json.Unmarshal(content, &revised); err != nil {
+		return nil, fmt.Errorf("invalid revision content: %w", err)
+	}
+	maps.Copy(fields, revised)
+
+	doc, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	var applied T
+	if err := json.Unmarshal(doc, &applied); err != nil {
+		return nil, fmt.Errorf("invalid revision content: %w", err)
+	}
+	return &applied, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, fmt.Errorf("document is not an object: %w", err)
+	}
+	return fields, nil
+}
+
+// ensurePublished records live, the stored state of an entity, as its
+// published revision if the entity has no revisions yet, so that the
+// changes made to it afterwards can be compared and rolled back.
+func ensurePublished(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64, live any) error {
+	content, err := revisionContent(entityType, live)
+	if err != nil {
+		return err
+	}
+	revision := &models.Revision{EntityType: entityType, EntityID: id, Content: content, CreatedBy: audit.Actor(ctx)}
+	if err := repo.EnsurePublished(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record published revision: %w", err)
+	}
+	return nil
+}
+
+// saveDraft stores draft, an edited copy of live, as the draft revision of
+// an entity. base is the content the edits were made to, or nil when that is
+// the content of live.
+func saveDraft(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64, live, draft any, base json.RawMessage) (*models.Revision, error) {
+	if err := ensurePublished(ctx, repo, entityType, id, live); err != nil {
+		return nil, err
+	}
+	content, err := revisionContent(entityType, draft)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		if base, err = revisionContent(entityType, live); err != nil {
+			return nil, err
+		}
+	}
+	revision := &models.Revision{
+		EntityType:  entityType,
+		EntityID:    id,
+		Content:     content,
+		BaseContent: &base,
+		CreatedBy:   audit.Actor(ctx),
+	}
+	if err := repo.SaveDraft(ctx, revision); err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	return revision, nil
+}
+
+// recordPublished records entity, just saved, as the published revision of
+// an entity. restoredFrom is the revision it was rolled back to, if any.
+func recordPublished(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64, entity any, restoredFrom *int) error {
+	content, err := revisionContent(entityType, entity)
+	if err != nil {
+		return err
+	}
+	revision := &models.Revision{
+		EntityType:   entityType,
+		EntityID:     id,
+		Content:      content,
+		RestoredFrom: restoredFrom,
+		CreatedBy:    audit.Actor(ctx),
+	}
+	if err := repo.CreatePublished(ctx, revision); err != nil {
+		return fmt.Errorf("failed to record published revision: %w", err)
+	}
+	return nil
+}
+
+// findRevision returns revision number of an entity, or its revision in
+// status when number is 0.
+func findRevision(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64, number int, status string) (*models.Revision, error) {
+	var revision *models.Revision
+	var err error
+	if number == 0 {
+		revision, err = repo.GetByStatus(ctx, entityType, id, status)
+	} else {
+		revision, err = repo.Get(ctx, entityType, id, number)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	if revision == nil {
+		if number == 0 {
+			return nil, fmt.Errorf("%s revision %w", status, ErrNotFound)
+		}
+		return nil, fmt.Errorf("revision %d %w", number, ErrNotFound)
+	}
+	return revision, nil
+}
+
+// diffRevisions compares revisions from and to of an entity, by default its
+// published revision and its draft.
+func diffRevisions(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64, from, to int) (*models.RevisionDiff, error) {
+	before, err := findRevision(ctx, repo, entityType, id, from, models.RevisionPublished)
+	if err != nil {
+		return nil, err
+	}
+	after, err := findRevision(ctx, repo, entityType, id, to, models.RevisionDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := audit.Diff(before.Content, after.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff revisions: %w", err)
+	}
+	doc, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode changes: %w", err)
+	}
+	return &models.RevisionDiff{
+		EntityType: entityType,
+		EntityID:   id,
+		From:       before.Number,
+		To:         after.Number,
+		Changes:    doc,
+	}, nil
+}
+
+// getDraft returns the draft of an entity, or nil when it has none.
+func getDraft(ctx context.Context, repo repositories.RevisionRepo, entityType string, id int64) (*models.Revision, error) {
+	draft, err := repo.GetByStatus(ctx, entityType, id, models.RevisionDraft)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+	return draft, nil
+}
+
+// draftEdits returns the content of the fields a draft changes from the
+// content it was made from, to be applied over live, the entity as it is
+// now. Fields changed live since, by a save, a rollback or the scheduler,
+// are left alone unless the draft changes them too; then it fails with
+// ErrPreconditionFailed, as publishing would undo that change.
+func draftEdits(entityType string, draft *models.Revision, live any) (json.RawMessage, error) {
+	if draft.BaseContent == nil {
+		return nil, fmt.Errorf("%w: the draft does not record what it was made from; save it again",
+			ErrPreconditionFailed)
+	}
+	current, err := revisionContent(entityType, live)
+	if err != nil {
+		return nil, err
+	}
+	edits, err := audit.Diff(*draft.BaseContent, draft.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff draft: %w", err)
+	}
+	moved, err := audit.Diff(*draft.BaseContent, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff draft: %w", err)
+	}
+
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(draft.Content, &content); err != nil {
+		return nil, fmt.Errorf("invalid revision content: %w", err)
+	}
+	var conflicts []string
+	for name := range content {
+		edit, ok := edits[name]
+		if !ok {
+			delete(content, name)
+			continue
+		}
+		if change, ok := moved[name]; ok && !reflect.DeepEqual(change.To, edit.To) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		return nil, fmt.Errorf("%w: %s of the %s changed since its draft was saved; save the draft again",
+			ErrPreconditionFailed, strings.Join(conflicts, ", "), entityType)
+	}
+	return json.Marshal(content)
+}
+
+// contentChanges returns the writable fields of an entity of entityType that
+// differ between before and after.
+func contentChanges(entityType string, before, after any) ([]string, error) {
+	from, err := revisionContent(entityType, before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := revisionContent(entityType, after)
+	if err != nil {
+		return nil, err
+	}
+	return changedFields(from, to, revisionFields[entityType])
+}
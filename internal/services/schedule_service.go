@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"server/internal/audit"
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// schedulerActor is the actor recorded for transitions the scheduler applies.
+const schedulerActor = "scheduler"
+
+// defaultScheduleWindow is how far ahead scheduled changes are listed by
+// default.
+const defaultScheduleWindow = 30 * 24 * time.Hour
+
+type ScheduleService struct {
+	tx           repositories.Transactor
+	scheduleRepo repositories.ScheduleRepo
+	categoryRepo repositories.CategoryRepo
+	serviceRepo  repositories.ServiceRepo
+	bundleRepo   repositories.BundleRepo
+	auditRepo    repositories.AuditRepo
+}
+
+func NewScheduleService(
+	tx repositories.Transactor,
+	scheduleRepo repositories.ScheduleRepo,
+	categoryRepo repositories.CategoryRepo,
+	serviceRepo repositories.ServiceRepo,
+	bundleRepo repositories.BundleRepo,
+	auditRepo repositories.AuditRepo,
+) *ScheduleService {
+	return &ScheduleService{
+		tx:           tx,
+		scheduleRepo: scheduleRepo,
+		categoryRepo: categoryRepo,
+		serviceRepo:  serviceRepo,
+		bundleRepo:   bundleRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+// ListScheduled returns the scheduled changes due by until, or within the
+// next 30 days when until is zero, soonest first. entityType narrows them to
+// categories or services.
+func (s *ScheduleService) ListScheduled(ctx context.Context, entityType string, until time.Time) ([]models.ScheduledChange, error) {
+	if entityType != "" && entityType != models.EntityCategory && entityType != models.EntityService {
+		return nil, fmt.Errorf("%w: entity_type must be %q or %q",
+			ErrInvalidListOptions, models.EntityCategory, models.EntityService)
+	}
+	if until.IsZero() {
+		until = time.Now().Add(defaultScheduleWindow)
+	}
+
+	changes, err := s.scheduleRepo.GetUpcoming(ctx, entityType, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled changes: %w", err)
+	}
+	return changes, nil
+}
+
+// ApplyDue carries out every scheduled transition that has come due, each
+// in its own transaction and recorded in the audit log, and returns how many
+// items it changed. A failing item does not stop the others.
+func (s *ScheduleService) ApplyDue(ctx context.Context) (int, error) {
+	due, err := s.scheduleRepo.GetUpcoming(ctx, "", time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due changes: %w", err)
+	}
+
+	ctx = audit.WithRequest(ctx, schedulerActor, "")
+	type key struct {
+		entityType string
+		id         int64
+	}
+	seen := make(map[key]bool, len(due))
+	applied := 0
+	var errs []error
+	for _, change := range due {
+		k := key{change.EntityType, change.EntityID}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		ok, err := s.apply(ctx, change.EntityType, change.EntityID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %d: %w", change.EntityType, change.EntityID, err))
+			continue
+		}
+		if ok {
+			applied++
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// RunScheduler applies due transitions every interval until ctx is done.
+func (s *ScheduleService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		applied, err := s.ApplyDue(ctx)
+		if err != nil {
+			log.Printf("Scheduled changes failed: %v", err)
+		}
+		if applied > 0 {
+			log.Printf("Applied scheduled changes to %d items", applied)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply carries out the due transitions of one item in a transaction of its
+// own.
+func (s *ScheduleService) apply(ctx context.Context, entityType string, id int64) (bool, error) {
+	applied := false
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if entityType == models.EntityCategory {
+			applied, err = s.applyCategory(ctx, id)
+		} else {
+			applied, err = s.applyService(ctx, id)
+		}
+		return err
+	})
+	return applied, err
+}
+
+func (s *ScheduleService) applyCategory(ctx context.Context, id int64) (bool, error) {
+	before, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get category: %w", err)
+	}
+	if before == nil {
+		return false, nil
+	}
+	wasActive, isActive, ok, err := s.scheduleRepo.Apply(ctx, models.EntityCategory, id)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// Reads show a due transition before it is applied, so the audit entry
+	// starts from the stored flag to show the change.
+	before.IsActive = wasActive
+	after, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get category: %w", err)
+	}
+	return true, recordAudit(ctx, s.auditRepo, scheduleAction(isActive), models.EntityCategory, id, before, after)
+}
+
+// applyService is applyCategory for services. A service switched off takes
+// the bundles containing it down with it.
+func (s *ScheduleService) applyService(ctx context.Context, id int64) (bool, error) {
+	before, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get service: %w", err)
+	}
+	if before == nil {
+		return false, nil
+	}
+	wasActive, isActive, ok, err := s.scheduleRepo.Apply(ctx, models.EntityService, id)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	before.IsActive = wasActive
+	if wasActive && !isActive {
+		if err := deactivateBundles(ctx, s.bundleRepo, before, "unpublished"); err != nil {
+			return false, err
+		}
+	}
+	after, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get service: %w", err)
+	}
+	return true, recordAudit(ctx, s.auditRepo, scheduleAction(isActive), models.EntityService, id, before, after)
+}
+
+// scheduleAction is the audit action of a transition leaving an item active
+// or not.
+func scheduleAction(isActive bool) string {
+	if isActive {
+		return models.AuditPublish
+	}
+	return models.AuditUnpublish
+}
+
+// validateSchedule checks the publish window of an item being saved. Times
+// that changed must lie in the future; unchanged ones may have come due and
+// are left for the scheduler.
+func validateSchedule(publishAt, unpublishAt, currentPublish, currentUnpublish *time.Time) error {
+	now := time.Now()
+	if publishAt != nil && !equalTime(publishAt, currentPublish) && !publishAt.After(now) {
+		return errors.New("publish_at must be in the future")
+	}
+	if unpublishAt != nil && !equalTime(unpublishAt, currentUnpublish) && !unpublishAt.After(now) {
+		return errors.New("unpublish_at must be in the future")
+	}
+	if publishAt != nil && unpublishAt != nil && publishAt.Equal(*unpublishAt) {
+		return errors.New("publish_at and unpublish_at cannot be the same time")
+	}
+	return nil
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+type SearchService struct {
+	repo repositories.SearchRepo
+}
+
+func NewSearchService(repo repositories.SearchRepo) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(ctx context.Context, opts models.SearchOptions) (*models.Page[models.SearchResult], error) {
+	opts.Query = strings.TrimSpace(opts.Query)
+	if opts.Query == "" {
+		return nil, fmt.Errorf("%w: search query cannot be empty", ErrInvalidListOptions)
+	}
+
+	switch opts.Type {
+	case "", models.SearchTypeCategory, models.SearchTypeService:
+	default:
+		return nil, fmt.Errorf("%w: unknown result type %q", ErrInvalidListOptions, opts.Type)
+	}
+
+	switch {
+	case opts.Limit == 0:
+		opts.Limit = defaultListLimit
+	case opts.Limit < 0 || opts.Limit > maxListLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListOptions, maxListLimit)
+	}
+
+	page, err := s.repo.Search(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search catalog: %w", listError(err))
+	}
+	return page, nil
+}
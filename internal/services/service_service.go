@@ -1,43 +1,98 @@
 package services
 
 import (
+	"server/internal/audit"
 	"server/internal/models"
+	"server/internal/patch"
 	"server/internal/repositories"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
+// defaultServiceDuration is used for services created without a duration.
+const defaultServiceDuration = 60
+
 type ServiceService struct {
-	serviceRepo  repositories.ServiceRepo
-	categoryRepo repositories.CategoryRepo
+	tx              repositories.Transactor
+	serviceRepo     repositories.ServiceRepo
+	categoryRepo    repositories.CategoryRepo
+	mediaRepo       repositories.MediaRepo
+	translationRepo repositories.TranslationRepo
+	auditRepo       repositories.AuditRepo
+	slugRepo        repositories.SlugRepo
+	optionRepo      repositories.OptionRepo
+	bundleRepo      repositories.BundleRepo
+	revisionRepo    repositories.RevisionRepo
 }
 
 func NewServiceService(
+	tx repositories.Transactor,
 	serviceRepo repositories.ServiceRepo,
 	categoryRepo repositories.CategoryRepo,
+	mediaRepo repositories.MediaRepo,
+	translationRepo repositories.TranslationRepo,
+	auditRepo repositories.AuditRepo,
+	slugRepo repositories.SlugRepo,
+	optionRepo repositories.OptionRepo,
+	bundleRepo repositories.BundleRepo,
+	revisionRepo repositories.RevisionRepo,
 ) *ServiceService {
 	return &ServiceService{
-		serviceRepo:  serviceRepo,
-		categoryRepo: categoryRepo,
+		tx:              tx,
+		serviceRepo:     serviceRepo,
+		categoryRepo:    categoryRepo,
+		mediaRepo:       mediaRepo,
+		translationRepo: translationRepo,
+		auditRepo:       auditRepo,
+		slugRepo:        slugRepo,
+		optionRepo:      optionRepo,
+		bundleRepo:      bundleRepo,
+		revisionRepo:    revisionRepo,
 	}
 }
 
+// CreateService adds a service, live straight away, and records it as its
+// first published revision.
 func (s *ServiceService) CreateService(ctx context.Context, req *models.Service) (*models.Service, error) {
 	if req.CategoryID == 0 {
 		return nil, errors.New("category ID is required")
 	}
 	
-	if _, err := s.categoryRepo.GetByID(ctx, req.CategoryID); err != nil {
-		return nil, fmt.Errorf("invalid category: %w", err)
+	if err := s.checkCategory(ctx, req.CategoryID); err != nil {
+		return nil, err
 	}
 
 	if req.Name == "" {
 		return nil, errors.New("service name cannot be empty")
 	}
 
-	if err := s.serviceRepo.Create(ctx, req); err != nil {
-		return nil, fmt.Errorf("failed to create service: %w", err)
+	if err := validatePricing(req, ""); err != nil {
+		return nil, err
+	}
+	if err := validateDuration(req); err != nil {
+		return nil, err
+	}
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt, nil, nil); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.setSlug(ctx, req, ""); err != nil {
+			return err
+		}
+		if err := s.serviceRepo.Create(ctx, req); err != nil {
+			return fmt.Errorf("failed to create service: %w", err)
+		}
+		if err := s.audit(ctx, models.AuditCreate, req.ID, nil, s.serviceRepo.GetByID); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityService, req.ID, req, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return req, nil
@@ -48,43 +103,580 @@ func (s *ServiceService) GetService(ctx context.Context, id int64) (*models.Serv
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service: %w", err)
 	}
+	if service != nil {
+		if err := s.loadDetails(ctx, service); err != nil {
+			return nil, err
+		}
+	}
 	return service, nil
 }
 
-func (s *ServiceService) ListServices(ctx context.Context) ([]models.Service, error) {
-	services, err := s.serviceRepo.GetAll(ctx)
+// GetServiceBySlug returns the service with the given slug. A slug the
+// service no longer has fails with a MovedError naming its current one.
+func (s *ServiceService) GetServiceBySlug(ctx context.Context, slug string) (*models.Service, error) {
+	service, err := s.serviceRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, movedError(ctx, s.slugRepo, models.EntityService, slug)
+	}
+	if err := s.loadDetails(ctx, service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// loadDetails adds the media, options and translations to a service being
+// served.
+func (s *ServiceService) loadDetails(ctx context.Context, service *models.Service) error {
+	var err error
+	if service.Media, err = loadMedia(ctx, s.mediaRepo.GetByService, service.ID); err != nil {
+		return err
+	}
+	if service.OptionGroups, err = s.optionRepo.GetByService(ctx, service.ID); err != nil {
+		return fmt.Errorf("failed to get option groups: %w", err)
+	}
+	return localizeServices(ctx, s.translationRepo, []*models.Service{service})
+}
+
+func (s *ServiceService) ListServices(ctx context.Context, opts models.ListOptions) (*models.Page[models.Service], error) {
+	if err := normalizeCatalogListOptions(&opts); err != nil {
+		return nil, err
+	}
+	if opts.Near != nil {
+		if err := validateGeoPoint(*opts.Near); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidListOptions, err)
+		}
+	}
+	opts.PostalCode = normalizePostalCode(opts.PostalCode)
+	if err := normalizeTagFilter(&opts); err != nil {
+		return nil, err
+	}
+
+	page, err := s.serviceRepo.GetAll(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services: %w", err)
+		return nil, fmt.Errorf("failed to list services: %w", listError(err))
+	}
+	if err := localizeServices(ctx, s.translationRepo, pointers(page.Items)); err != nil {
+		return nil, err
 	}
-	return services, nil
+	return page, nil
 }
 
-func (s *ServiceService) ListServicesByCategory(ctx context.Context, categoryID int64) ([]models.Service, error) {
-	services, err := s.serviceRepo.GetByCategory(ctx, categoryID)
+// ListServicesByCategory lists the services and bundles of a category,
+// optionally including those of every category nested below it. Only the
+// is_active and tag filters of opts apply, the tag filters to services alone.
+func (s *ServiceService) ListServicesByCategory(ctx context.Context, categoryID int64, includeDescendants bool, opts models.ListOptions) (*models.CategoryListing, error) {
+	if err := normalizeTagFilter(&opts); err != nil {
+		return nil, err
+	}
+
+	filter := models.ListOptions{IsActive: opts.IsActive, Tags: opts.Tags, TagMatch: opts.TagMatch}
+	services, err := s.serviceRepo.GetByCategory(ctx, categoryID, includeDescendants, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list services by category: %w", err)
 	}
-	return services, nil
+	if services == nil {
+		services = []models.Service{}
+	}
+	if err := localizeServices(ctx, s.translationRepo, pointers(services)); err != nil {
+		return nil, err
+	}
+
+	bundles, err := s.bundleRepo.GetByCategory(ctx, categoryID, includeDescendants, opts.IsActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bundles by category: %w", err)
+	}
+	if err := priceBundles(ctx, s.bundleRepo, pointers(bundles)); err != nil {
+		return nil, err
+	}
+	return &models.CategoryListing{Services: services, Bundles: bundles}, nil
 }
 
+// ReorderServices sets the display order of the services of a category to
+// that of ids, which must list each of its live services exactly once.
+func (s *ServiceService) ReorderServices(ctx context.Context, categoryID int64, ids []int64) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		category, err := s.categoryRepo.GetByID(ctx, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return fmt.Errorf("category %w", ErrNotFound)
+		}
+
+		services, err := s.serviceRepo.GetByCategory(ctx, categoryID, false, models.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		current := make([]int64, len(services))
+		byID := make(map[int64]*models.Service, len(services))
+		for i := range services {
+			current[i] = services[i].ID
+			byID[services[i].ID] = &services[i]
+		}
+		if err := checkReorder(ids, current); err != nil {
+			return err
+		}
+
+		if err := s.serviceRepo.Reorder(ctx, ids); err != nil {
+			return fmt.Errorf("failed to reorder services: %w", err)
+		}
+		for i, id := range ids {
+			if before := byID[id]; before.SortOrder != i+1 {
+				if err := s.audit(ctx, models.AuditUpdate, id, before, s.serviceRepo.GetByID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateService saves a service straight away, bypassing its draft, and
+// records it as the published revision. Deactivating it deactivates the
+// bundles containing it.
 func (s *ServiceService) UpdateService(ctx context.Context, req *models.Service) error {
 	if req.ID == 0 {
 		return errors.New("invalid service ID")
 	}
-	
-	if err := s.serviceRepo.Update(ctx, req); err != nil {
-		return fmt.Errorf("failed to update service: %w", err)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Version, err = matchVersion(before.Version, req.Version); err != nil {
+			return err
+		}
+		if err := ensurePublished(ctx, s.revisionRepo, models.EntityService, req.ID, before); err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditUpdate, before, req, nil); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityService, req.ID, req, nil)
+	})
+}
+
+// SaveServiceDraft validates req and saves it as the draft revision of the
+// service, replacing any draft it had. The live service, which customers
+// see, is left as it is until the draft is published. req must be based on
+// the live service at req.Version, or any version when that is 0.
+func (s *ServiceService) SaveServiceDraft(ctx context.Context, req *models.Service) (*models.Revision, error) {
+	if req.ID == 0 {
+		return nil, errors.New("invalid service ID")
 	}
-	return nil
+
+	var draft *models.Revision
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		live, err := s.getLive(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Version, err = matchVersion(live.Version, req.Version); err != nil {
+			return err
+		}
+		if err := s.checkUpdate(ctx, req, live.Currency); err != nil {
+			return err
+		}
+		if err := validateSchedule(req.PublishAt, req.UnpublishAt, live.PublishAt, live.UnpublishAt); err != nil {
+			return err
+		}
+		if err := s.setSlug(ctx, req, live.Slug); err != nil {
+			return err
+		}
+		draft, err = saveDraft(ctx, s.revisionRepo, models.EntityService, req.ID, live, req, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
 }
 
-func (s *ServiceService) DeleteService(ctx context.Context, id int64) error {
+// PatchServiceDraft applies a merge patch or JSON patch to the draft of a
+// service, or to the live service when it has no draft, and saves the result
+// as its draft. version is checked against the live service, 0 matching any
+// version. It returns the service as the draft would make it.
+func (s *ServiceService) PatchServiceDraft(ctx context.Context, id, version int64, p patch.Patch) (*models.Service, error) {
+	var patched *models.Service
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		live, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := matchVersion(live.Version, version); err != nil {
+			return err
+		}
+		current, base, err := s.withDraft(ctx, live)
+		if err != nil {
+			return err
+		}
+
+		if patched, err = applyPatch(current, p); err != nil {
+			return err
+		}
+		// Validation normalizes some fields, so it runs before looking for
+		// what changed.
+		if err := s.checkUpdate(ctx, patched, live.Currency); err != nil {
+			return err
+		}
+		if err := validateSchedule(patched.PublishAt, patched.UnpublishAt, live.PublishAt, live.UnpublishAt); err != nil {
+			return err
+		}
+		if patched.Slug != live.Slug {
+			if err := checkSlug(ctx, s.slugRepo, models.EntityService, id, patched.Slug); err != nil {
+				return err
+			}
+		}
+		fields, err := changedFields(current, patched, servicePatchFields)
+		if err != nil || len(fields) == 0 {
+			return err
+		}
+
+		_, err = saveDraft(ctx, s.revisionRepo, models.EntityService, id, live, patched, base)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// ApplyServiceDraft returns a copy of service, as served, showing the
+// content of its draft.
+func (s *ServiceService) ApplyServiceDraft(ctx context.Context, service *models.Service) (*models.Service, error) {
+	draft, err := findRevision(ctx, s.revisionRepo, models.EntityService, service.ID, 0, models.RevisionDraft)
+	if err != nil {
+		return nil, err
+	}
+	return applyRevision(service, draft.Content)
+}
+
+// PublishService makes the draft of a service live and its published
+// revision.
+func (s *ServiceService) PublishService(ctx context.Context, id int64) (*models.Service, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		draft, err := s.revisionRepo.GetByStatus(ctx, models.EntityService, id, models.RevisionDraft)
+		if err != nil {
+			return fmt.Errorf("failed to get draft: %w", err)
+		}
+		if draft == nil {
+			return fmt.Errorf("%w: service has no draft to publish", ErrConflict)
+		}
+		edits, err := draftEdits(models.EntityService, draft, before)
+		if err != nil {
+			return err
+		}
+
+		req, err := applyRevision(before, edits)
+		if err != nil {
+			return err
+		}
+		columns, err := contentChanges(models.EntityService, before, req)
+		if err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditPublish, before, req, columns); err != nil {
+			return err
+		}
+		if err := s.revisionRepo.Publish(ctx, draft, audit.Actor(ctx)); err != nil {
+			return fmt.Errorf("failed to publish draft: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetService(ctx, id)
+}
+
+// RollbackService makes an earlier revision of a service live again,
+// recording it as a new published revision. The schedule of the service and
+// any pending draft are left as they are.
+func (s *ServiceService) RollbackService(ctx context.Context, id int64, number int) (*models.Service, error) {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.getLive(ctx, id)
+		if err != nil {
+			return err
+		}
+		revision, err := findRevision(ctx, s.revisionRepo, models.EntityService, id, number, "")
+		if err != nil {
+			return err
+		}
+		if revision.Status == models.RevisionDraft {
+			return fmt.Errorf("%w: revision %d is the draft; publish it instead", ErrConflict, number)
+		}
+
+		req, err := applyRevision(before, revision.Content)
+		if err != nil {
+			return err
+		}
+		req.PublishAt, req.UnpublishAt = before.PublishAt, before.UnpublishAt
+		columns, err := contentChanges(models.EntityService, before, req)
+		if err != nil {
+			return err
+		}
+		if err := s.save(ctx, models.AuditPublish, before, req, columns); err != nil {
+			return err
+		}
+		return recordPublished(ctx, s.revisionRepo, models.EntityService, id, req, &number)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetService(ctx, id)
+}
+
+// ListServiceRevisions returns the revisions of a service, newest first.
+func (s *ServiceService) ListServiceRevisions(ctx context.Context, id int64) ([]models.Revision, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.GetByEntity(ctx, models.EntityService, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// DiffServiceRevisions compares two revisions of a service, by default the
+// published one and the draft.
+func (s *ServiceService) DiffServiceRevisions(ctx context.Context, id int64, from, to int) (*models.RevisionDiff, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	return diffRevisions(ctx, s.revisionRepo, models.EntityService, id, from, to)
+}
+
+// DeleteService moves a service to the trash if it is at version, or at any
+// version when version is 0. Bundles containing it are deactivated.
+func (s *ServiceService) DeleteService(ctx context.Context, id, version int64) error {
 	if id == 0 {
 		return errors.New("invalid service ID")
 	}
-	
-	if err := s.serviceRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete service: %w", err)
+
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.serviceRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get service: %w", err)
+		}
+		if before == nil {
+			return fmt.Errorf("service %w", ErrNotFound)
+		}
+		if version, err = matchVersion(before.Version, version); err != nil {
+			return err
+		}
+		if err := s.serviceRepo.Delete(ctx, id, version); err != nil {
+			return fmt.Errorf("failed to delete service: %w", staleError(err))
+		}
+		if err := deactivateBundles(ctx, s.bundleRepo, before, "deleted"); err != nil {
+			return err
+		}
+		return s.audit(ctx, models.AuditDelete, id, before, s.serviceRepo.GetTrashed)
+	})
+}
+
+// RestoreService takes a service back out of the trash. Its category must
+// not be in the trash itself.
+func (s *ServiceService) RestoreService(ctx context.Context, id int64) (*models.Service, error) {
+	service, err := s.serviceRepo.GetTrashed(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("deleted service %w", ErrNotFound)
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, service.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify category: %w", err)
+	}
+	if category == nil {
+		return nil, fmt.Errorf("%w: category %q is deleted; restore it or move the service first",
+			ErrConflict, service.CategoryName)
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.serviceRepo.Restore(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrDuplicate) {
+				return fmt.Errorf("%w: another service is already named %q", ErrConflict, service.Name)
+			}
+			return fmt.Errorf("failed to restore service: %w", err)
+		}
+		return s.audit(ctx, models.AuditRestore, id, service, s.serviceRepo.GetByID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	return restored, nil
+}
+
+// ListServicePrices returns the full price history of a service, newest first.
+func (s *ServiceService) ListServicePrices(ctx context.Context, id int64) ([]models.ServicePrice, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	prices, err := s.serviceRepo.GetPriceHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service prices: %w", err)
+	}
+	return prices, nil
+}
+
+// GetServicePriceAt returns the price that was in effect for a service at the
+// given moment.
+func (s *ServiceService) GetServicePriceAt(ctx context.Context, id int64, at time.Time) (*models.ServicePrice, error) {
+	if _, err := s.getLive(ctx, id); err != nil {
+		return nil, err
+	}
+	price, err := s.serviceRepo.GetPriceAt(ctx, id, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service price: %w", err)
+	}
+	if price == nil {
+		return nil, errors.New("no price in effect at that time")
+	}
+	return price, nil
+}
+
+// save writes req over before, the live service, within the transaction
+// carried by ctx and audits it as action. Given columns, only those are
+// written, and nothing when there are none. Deactivating the service
+// deactivates the bundles containing it.
+func (s *ServiceService) save(ctx context.Context, action string, before, req *models.Service, columns []string) error {
+	if err := s.checkUpdate(ctx, req, before.Currency); err != nil {
+		return err
+	}
+	if err := validateSchedule(req.PublishAt, req.UnpublishAt, before.PublishAt, before.UnpublishAt); err != nil {
+		return err
+	}
+	if err := s.setSlug(ctx, req, before.Slug); err != nil {
+		return err
+	}
+	var err error
+	switch {
+	case columns == nil:
+		err = s.serviceRepo.Update(ctx, req)
+	case len(columns) > 0:
+		err = s.serviceRepo.Patch(ctx, req, columns)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", staleError(err))
+	}
+	if before.IsActive && !req.IsActive {
+		if err := deactivateBundles(ctx, s.bundleRepo, before, "deactivated"); err != nil {
+			return err
+		}
+	}
+	return s.audit(ctx, action, req.ID, before, s.serviceRepo.GetByID)
+}
+
+// getLive returns the stored service, failing with ErrNotFound when there
+// is none.
+func (s *ServiceService) getLive(ctx context.Context, id int64) (*models.Service, error) {
+	service, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return nil, fmt.Errorf("service %w", ErrNotFound)
+	}
+	return service, nil
+}
+
+// withDraft returns live with the content of its draft, if it has one, and
+// the content the draft was made from, or nil when there is no draft.
+func (s *ServiceService) withDraft(ctx context.Context, live *models.Service) (*models.Service, json.RawMessage, error) {
+	draft, err := getDraft(ctx, s.revisionRepo, models.EntityService, live.ID)
+	if err != nil || draft == nil {
+		return live, nil, err
+	}
+	applied, err := applyRevision(live, draft.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	if draft.BaseContent == nil {
+		return applied, nil, nil
+	}
+	return applied, *draft.BaseContent, nil
+}
+
+// audit records a change to a service, reading its new state with load
+// inside the transaction carried by ctx.
+func (s *ServiceService) audit(ctx context.Context, action string, id int64, before *models.Service,
+	load func(context.Context, int64) (*models.Service, error)) error {
+	after, err := load(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+	return recordAudit(ctx, s.auditRepo, action, models.EntityService, id, before, after)
+}
+
+// setSlug fills in the slug of a service being saved: a new one made from
+// the name if none is given, or the current one on update. A slug given
+// explicitly is checked.
+func (s *ServiceService) setSlug(ctx context.Context, service *models.Service, current string) error {
+	var err error
+	switch {
+	case service.Slug == "" && current != "":
+		service.Slug = current
+	case service.Slug == "":
+		service.Slug, err = newSlug(ctx, s.slugRepo, models.EntityService, service.Name)
+	case service.Slug != current:
+		err = checkSlug(ctx, s.slugRepo, models.EntityService, service.ID, service.Slug)
+	}
+	return err
+}
+
+// checkUpdate validates a service about to replace its stored version,
+// normalizing its pricing and duration. currency is the stored currency,
+// kept when req leaves it out.
+func (s *ServiceService) checkUpdate(ctx context.Context, req *models.Service, currency string) error {
+	if req.Name == "" {
+		return errors.New("service name cannot be empty")
+	}
+	if err := validatePricing(req, currency); err != nil {
+		return err
+	}
+	if err := validateDuration(req); err != nil {
+		return err
+	}
+	return s.checkCategory(ctx, req.CategoryID)
+}
+
+// checkCategory verifies that a service's category exists and is not in the
+// trash.
+func (s *ServiceService) checkCategory(ctx context.Context, categoryID int64) error {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return fmt.Errorf("invalid category: %w", err)
+	}
+	if category == nil {
+		return errors.New("invalid category: category not found")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func validateDuration(req *models.Service) error {
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = defaultServiceDuration
+	}
+	if req.DurationMinutes < 0 {
+		return errors.New("service duration must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"server/internal/repositories"
+	"server/internal/slug"
+)
+
+// slugBatch is how many numbered candidates newSlug checks per query.
+const slugBatch = 20
+
+// MovedError is returned when an entity is looked up by a slug it has given
+// up. Slug is the one it has now.
+type MovedError struct {
+	ID   int64
+	Slug string
+}
+
+func (e *MovedError) Error() string {
+	return fmt.Sprintf("moved to %q", e.Slug)
+}
+
+// newSlug makes a slug for an entity of the type from its name, numbering it
+// when the plain one is or was in use. Names leaving nothing usable fall
+// back to the entity type, ahead of whatever digits they had as long as
+// those fit.
+func newSlug(ctx context.Context, repo repositories.SlugRepo, entityType, name string) (string, error) {
+	base := slug.Make(name)
+	if !slug.Valid(base) {
+		base = slug.Make(entityType + "-" + base)
+	}
+
+	for n := 1; ; n += slugBatch {
+		candidates := make([]string, 0, slugBatch)
+		for i := n; i < n+slugBatch; i++ {
+			if i == 1 {
+				candidates = append(candidates, base)
+			} else {
+				candidates = append(candidates, slug.WithSuffix(base, i))
+			}
+		}
+		taken, err := repo.Taken(ctx, entityType, candidates)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug: %w", err)
+		}
+		for _, c := range candidates {
+			if !slices.Contains(taken, c) {
+				return c, nil
+			}
+		}
+	}
+}
+
+// checkSlug validates a slug chosen for entity id of the type, which is 0 for
+// a new entity. Slugs retired by other entities may be taken over, but not
+// those another entity has, even in the trash.
+func checkSlug(ctx context.Context, repo repositories.SlugRepo, entityType string, id int64, s string) error {
+	if !slug.Valid(s) {
+		return fmt.Errorf("invalid slug %q: use lowercase letters, digits and single hyphens, not only digits, up to %d characters",
+			s, slug.MaxLength)
+	}
+	owner, err := repo.Owner(ctx, entityType, s)
+	if err != nil {
+		return fmt.Errorf("failed to check slug: %w", err)
+	}
+	if owner != 0 && owner != id {
+		return fmt.Errorf("%w: slug %q is already in use", ErrConflict, s)
+	}
+	return nil
+}
+
+// movedError returns a MovedError if slug used to lead to a live entity of
+// the type, or a not found error otherwise.
+func movedError(ctx context.Context, repo repositories.SlugRepo, entityType, s string) error {
+	id, current, err := repo.Redirect(ctx, entityType, s)
+	if err != nil {
+		return fmt.Errorf("failed to resolve slug: %w", err)
+	}
+	if id == 0 {
+		return fmt.Errorf("%s %w", entityType, ErrNotFound)
+	}
+	return &MovedError{ID: id, Slug: current}
+}
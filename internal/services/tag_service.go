@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"server/internal/models"
+	"server/internal/repositories"
+	"server/internal/slug"
+)
+
+// maxTagName bounds the length of tag names, which are shown as chips.
+const maxTagName = 50
+
+type TagService struct {
+	tagRepo     repositories.TagRepo
+	serviceRepo repositories.ServiceRepo
+}
+
+func NewTagService(tagRepo repositories.TagRepo, serviceRepo repositories.ServiceRepo) *TagService {
+	return &TagService{tagRepo: tagRepo, serviceRepo: serviceRepo}
+}
+
+// ListTags returns every tag with its usage, sorted by name or, for usage,
+// most used first.
+func (s *TagService) ListTags(ctx context.Context, sort string) ([]models.Tag, error) {
+	if sort == "" {
+		sort = models.SortName
+	}
+	if sort != models.SortName && sort != models.SortUsage {
+		return nil, fmt.Errorf("%w: sort must be %q or %q", ErrInvalidListOptions, models.SortName, models.SortUsage)
+	}
+
+	tags, err := s.tagRepo.GetAll(ctx, sort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	return tags, nil
+}
+
+// CreateTag adds a tag, making its slug from the name unless one is given.
+func (s *TagService) CreateTag(ctx context.Context, req *models.Tag) (*models.Tag, error) {
+	if err := checkTag(req, ""); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.Create(ctx, req); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: tag %q already exists", ErrConflict, req.Slug)
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return req, nil
+}
+
+// UpdateTag renames a tag. Its slug, which filters use, only changes when a
+// new one is given.
+func (s *TagService) UpdateTag(ctx context.Context, req *models.Tag) (*models.Tag, error) {
+	current, err := s.tagRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("tag %w", ErrNotFound)
+	}
+	if err := checkTag(req, current.Slug); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Update(ctx, req); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrDuplicate):
+			return nil, fmt.Errorf("%w: tag %q already exists", ErrConflict, req.Slug)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("tag %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+	req.CreatedAt, req.ServiceCount = current.CreatedAt, current.ServiceCount
+	return req, nil
+}
+
+// DeleteTag removes a tag, taking it off every service carrying it.
+func (s *TagService) DeleteTag(ctx context.Context, id int64) error {
+	if err := s.tagRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("tag %w", ErrNotFound)
+		}
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (s *TagService) ListServiceTags(ctx context.Context, serviceID int64) ([]models.Tag, error) {
+	if err := s.checkService(ctx, serviceID); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.GetByService(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service tags: %w", err)
+	}
+	return tags, nil
+}
+
+// AttachTag puts a tag on a service, if it does not carry it already.
+func (s *TagService) AttachTag(ctx context.Context, serviceID, tagID int64) error {
+	if err := s.checkService(ctx, serviceID); err != nil {
+		return err
+	}
+	tag, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to get tag: %w", err)
+	}
+	if tag == nil {
+		return fmt.Errorf("tag %w", ErrNotFound)
+	}
+
+	if err := s.tagRepo.Attach(ctx, serviceID, tagID); err != nil {
+		return fmt.Errorf("failed to attach tag: %w", err)
+	}
+	return nil
+}
+
+// DetachTag takes a tag off a service, if it carries it.
+func (s *TagService) DetachTag(ctx context.Context, serviceID, tagID int64) error {
+	if err := s.checkService(ctx, serviceID); err != nil {
+		return err
+	}
+	if err := s.tagRepo.Detach(ctx, serviceID, tagID); err != nil {
+		return fmt.Errorf("failed to detach tag: %w", err)
+	}
+	return nil
+}
+
+func (s *TagService) checkService(ctx context.Context, serviceID int64) error {
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %w", err)
+	}
+	if service == nil {
+		return fmt.Errorf("service %w", ErrNotFound)
+	}
+	return nil
+}
+
+// checkTag validates a tag about to be saved, filling in its slug: the
+// current one if any, or else one made from the name.
+func checkTag(tag *models.Tag, current string) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.Name == "" {
+		return errors.New("tag name cannot be empty")
+	}
+	if utf8.RuneCountInString(tag.Name) > maxTagName {
+		return fmt.Errorf("tag name cannot be longer than %d characters", maxTagName)
+	}
+
+	switch {
+	case tag.Slug != "":
+	case current != "":
+		tag.Slug = current
+	default:
+		tag.Slug = slug.Make(tag.Name)
+	}
+	if !slug.Valid(tag.Slug) {
+		return fmt.Errorf("invalid tag slug %q: use lowercase letters, digits and single hyphens, not only digits", tag.Slug)
+	}
+	return nil
+}
+
+// normalizeTagFilter validates the tag filter of opts, dropping blank and
+// repeated slugs.
+func normalizeTagFilter(opts *models.ListOptions) error {
+	switch opts.TagMatch {
+	case "":
+		opts.TagMatch = models.TagMatchAny
+	case models.TagMatchAny, models.TagMatchAll:
+	default:
+		return fmt.Errorf("%w: tag_match must be %q or %q", ErrInvalidListOptions, models.TagMatchAny, models.TagMatchAll)
+	}
+
+	tags := make([]string, 0, len(opts.Tags))
+	for _, t := range opts.Tags {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" && !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	opts.Tags = tags
+	return nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"server/internal/i18n"
+	"server/internal/models"
+	"server/internal/repositories"
+)
+
+// maxTranslatedNameLength matches the length of the name columns.
+const maxTranslatedNameLength = 255
+
+type TranslationService struct {
+	tx              repositories.Transactor
+	translationRepo repositories.TranslationRepo
+	categoryRepo    repositories.CategoryRepo
+	serviceRepo     repositories.ServiceRepo
+}
+
+func NewTranslationService(
+	tx repositories.Transactor,
+	translationRepo repositories.TranslationRepo,
+	categoryRepo repositories.CategoryRepo,
+	serviceRepo repositories.ServiceRepo,
+) *TranslationService {
+	return &TranslationService{
+		tx:              tx,
+		translationRepo: translationRepo,
+		categoryRepo:    categoryRepo,
+		serviceRepo:     serviceRepo,
+	}
+}
+
+// ListTranslations returns every translation of an entity, ordered by locale.
+func (s *TranslationService) ListTranslations(ctx context.Context, entityType string, entityID int64) ([]models.Translation, error) {
+	if err := s.checkEntity(ctx, entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	translations, err := s.translationRepo.GetByEntity(ctx, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	if translations == nil {
+		translations = []models.Translation{}
+	}
+	return translations, nil
+}
+
+// SetTranslations replaces an entity's translations in one locale with the
+// given field values. Fields left empty fall back to other locales.
+func (s *TranslationService) SetTranslations(ctx context.Context, entityType string, entityID int64, locale string, fields map[string]string) ([]models.Translation, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		if err := validateTranslation(field, value); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkEntity(ctx, entityType, entityID); err != nil {
+		return nil, err
+	}
+
+	translations := []models.Translation{}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.translationRepo.DeleteLocale(ctx, entityType, entityID, locale); err != nil {
+			return fmt.Errorf("failed to replace translations: %w", err)
+		}
+		for _, field := range []string{models.FieldName, models.FieldDescription} {
+			value := strings.TrimSpace(fields[field])
+			if value == "" {
+				continue
+			}
+			t := models.Translation{
+				EntityType: entityType,
+				EntityID:   entityID,
+				Locale:     locale,
+				Field:      field,
+				Value:      value,
+			}
+			if err := s.translationRepo.Upsert(ctx, &t); err != nil {
+				return fmt.Errorf("failed to save translation: %w", err)
+			}
+			translations = append(translations, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// DeleteTranslations removes every translation of an entity in one locale.
+func (s *TranslationService) DeleteTranslations(ctx context.Context, entityType string, entityID int64, locale string) error {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.translationRepo.DeleteLocale(ctx, entityType, entityID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete translations: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("translation %w", ErrNotFound)
+	}
+	return nil
+}
+
+// MissingTranslations reports the categories and services, or only those of
+// entityType when it is set, with fields that readers of locale would see in
+// the default locale. A translation into one of the locale's fallbacks, such
+// as fr for fr-CA, counts as covering it.
+func (s *TranslationService) MissingTranslations(ctx context.Context, locale, entityType string) (*models.TranslationReport, error) {
+	locale, err := translationLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	types := []string{models.EntityCategory, models.EntityService}
+	if entityType != "" {
+		if !isTranslatedEntity(entityType) {
+			return nil, fmt.Errorf("invalid entity type %q", entityType)
+		}
+		types = []string{entityType}
+	}
+
+	report := &models.TranslationReport{Locale: locale, Missing: []models.MissingTranslation{}}
+	locales := i18n.Chain(locale)
+	for _, t := range types {
+		coverage, err := s.translationRepo.GetCoverage(ctx, t, locales)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get translation coverage: %w", err)
+		}
+		for _, entity := range coverage {
+			report.Entities++
+			if len(entity.Fields) == 0 {
+				report.Complete++
+				continue
+			}
+			report.Missing = append(report.Missing, entity)
+		}
+	}
+	return report, nil
+}
+
+func (s *TranslationService) checkEntity(ctx context.Context, entityType string, entityID int64) error {
+	switch entityType {
+	case models.EntityCategory:
+		category, err := s.categoryRepo.GetByID(ctx, entityID)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+		if category == nil {
+			return fmt.Errorf("category %w", ErrNotFound)
+		}
+	case models.EntityService:
+		service, err := s.serviceRepo.GetByID(ctx, entityID)
+		if err != nil {
+			return fmt.Errorf("failed to get service: %w", err)
+		}
+		if service == nil {
+			return fmt.Errorf("service %w", ErrNotFound)
+		}
+	default:
+		return fmt.Errorf("invalid entity type %q", entityType)
+	}
+	return nil
+}
+
+// translationLocale normalises a locale that translations can be stored
+// under. Default-locale content lives on the entities themselves.
+func translationLocale(locale string) (string, error) {
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return "", err
+	}
+	if locale == i18n.DefaultLocale {
+		return "", fmt.Errorf("%s content is edited on the entity itself", i18n.DefaultLocale)
+	}
+	return locale, nil
+}
+
+func validateTranslation(field, value string) error {
+	switch field {
+	case models.FieldName:
+		if utf8.RuneCountInString(value) > maxTranslatedNameLength {
+			return fmt.Errorf("translated name cannot exceed %d characters", maxTranslatedNameLength)
+		}
+	case models.FieldDescription:
+	default:
+		return fmt.Errorf("field %q cannot be translated", field)
+	}
+	return nil
+}
+
+func isTranslatedEntity(entityType string) bool {
+	return entityType == models.EntityCategory || entityType == models.EntityService
+}
+
+// lookupTranslations returns, for each of the given entities, the best
+// translation of each field along the locale chain carried by ctx. It
+// returns nothing when the client asked for the default locale.
+func lookupTranslations(ctx context.Context, repo repositories.TranslationRepo, entityType string, ids []int64) (map[int64]map[string]string, error) {
+	locales := i18n.Locales(ctx)
+	if len(locales) == 0 || len(ids) == 0 {
+		return nil, nil
+	}
+
+	translations, err := repo.GetForEntities(ctx, entityType, ids, locales)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+
+	rank := make(map[string]int, len(locales))
+	for i, locale := range locales {
+		rank[locale] = i
+	}
+	type key struct {
+		id    int64
+		field string
+	}
+	best := make(map[key]models.Translation)
+	for _, t := range translations {
+		k := key{t.EntityID, t.Field}
+		if cur, ok := best[k]; !ok || rank[t.Locale] < rank[cur.Locale] {
+			best[k] = t
+		}
+	}
+
+	values := make(map[int64]map[string]string)
+	for k, t := range best {
+		if values[k.id] == nil {
+			values[k.id] = make(map[string]string)
+		}
+		values[k.id][k.field] = t.Value
+	}
+	return values, nil
+}
+
+// localizeCategories translates categories in place for the locales carried
+// by ctx.
+func localizeCategories(ctx context.Context, repo repositories.TranslationRepo, categories []*models.Category) error {
+	ids := make([]int64, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	values, err := lookupTranslations(ctx, repo, models.EntityCategory, ids)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		applyTranslation(values[c.ID], &c.Name, &c.Description)
+	}
+	return nil
+}
+
+// localizeServices translates services, and the category names embedded in
+// them, in place for the locales carried by ctx.
+func localizeServices(ctx context.Context, repo repositories.TranslationRepo, services []*models.Service) error {
+	ids := make([]int64, len(services))
+	categoryIDs := make([]int64, len(services))
+	for i, s := range services {
+		ids[i], categoryIDs[i] = s.ID, s.CategoryID
+	}
+	values, err := lookupTranslations(ctx, repo, models.EntityService, ids)
+	if err != nil {
+		return err
+	}
+	categoryValues, err := lookupTranslations(ctx, repo, models.EntityCategory, categoryIDs)
+	if err != nil {
+		return err
+	}
+	for _, s := range services {
+		applyTranslation(values[s.ID], &s.Name, &s.Description)
+		if name, ok := categoryValues[s.CategoryID][models.FieldName]; ok && s.CategoryName != "" {
+			s.CategoryName = name
+		}
+	}
+	return nil
+}
+
+func applyTranslation(values map[string]string, name, description *string) {
+	if v, ok := values[models.FieldName]; ok {
+		*name = v
+	}
+	if v, ok := values[models.FieldDescription]; ok {
+		*description = v
+	}
+}
+
+// pointers returns pointers to the elements of items, for localizing them in
+// place.
+func pointers[T any](items []T) []*T {
+	ptrs := make([]*T, len(items))
+	for i := range items {
+		ptrs[i] = &items[i]
+	}
+	return ptrs
+}
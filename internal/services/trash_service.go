@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"server/internal/models"
+	"server/internal/repositories"
+	"server/internal/storage"
+)
+
+type TrashService struct {
+	tx        repositories.Transactor
+	trashRepo repositories.TrashRepo
+	store     storage.Storage
+	retention time.Duration
+}
+
+func NewTrashService(
+	tx repositories.Transactor,
+	trashRepo repositories.TrashRepo,
+	store storage.Storage,
+	retention time.Duration,
+) *TrashService {
+	return &TrashService{
+		tx:        tx,
+		trashRepo: trashRepo,
+		store:     store,
+		retention: retention,
+	}
+}
+
+// ListTrash lists trashed categories and services, or only those of
+// entityType when it is set, most recently deleted first by default. Items
+// the purge keeps say why instead of when they will be purged.
+func (s *TrashService) ListTrash(ctx context.Context, entityType string, opts models.ListOptions) (*models.Page[models.TrashItem], error) {
+	if opts.Order == "" {
+		opts.Order = models.OrderDesc
+	}
+	if err := normalizeListOptionsWith(&opts, models.SortDeletedAt); err != nil {
+		return nil, err
+	}
+	if entityType != "" && entityType != models.EntityCategory && entityType != models.EntityService {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidListOptions, entityType)
+	}
+
+	page, err := s.trashRepo.GetAll(ctx, entityType, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", listError(err))
+	}
+	for i := range page.Items {
+		item := &page.Items[i]
+		if item.KeptBecause == "" {
+			purgeAfter := item.DeletedAt.Add(s.retention)
+			item.PurgeAfter = &purgeAfter
+		}
+	}
+	return page, nil
+}
+
+// PurgeExpired permanently deletes categories and services that have been
+// in the trash for longer than the retention period, along with their
+// stored media files.
+func (s *TrashService) PurgeExpired(ctx context.Context) (*models.PurgeResult, error) {
+	before := time.Now().Add(-s.retention)
+	result := &models.PurgeResult{}
+
+	var purged []models.Media
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		media, err := s.trashRepo.GetExpiredMedia(ctx, before)
+		if err != nil {
+			return fmt.Errorf("failed to list expired media: %w", err)
+		}
+
+		// Services go first so that categories they emptied can follow.
+		if result.Services, err = s.trashRepo.PurgeServices(ctx, before); err != nil {
+			return fmt.Errorf("failed to purge services: %w", err)
+		}
+		if result.Categories, err = s.trashRepo.PurgeCategories(ctx, before); err != nil {
+			return fmt.Errorf("failed to purge categories: %w", err)
+		}
+
+		// Media of owners that had to be kept are still there.
+		ids := make([]int64, len(media))
+		for i, m := range media {
+			ids[i] = m.ID
+		}
+		remaining, err := s.trashRepo.GetRemainingMedia(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to check purged media: %w", err)
+		}
+		kept := make(map[int64]bool, len(remaining))
+		for _, id := range remaining {
+			kept[id] = true
+		}
+		for _, m := range media {
+			if !kept[m.ID] {
+				purged = append(purged, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Blobs are only removed once the rows referring to them are gone for good.
+	for i := range purged {
+		removeMediaBlobs(ctx, s.store, &purged[i])
+	}
+	return result, nil
+}
+
+// RunPurge purges expired trash every interval until ctx is done.
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if result.Categories > 0 || result.Services > 0 {
+			log.Printf("Purged %d categories and %d services from the trash", result.Categories, result.Services)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// matchVersion checks a write made against version of an entity that is at
+// current, 0 matching any version, and returns the version to write against.
+func matchVersion(current, version int64) (int64, error) {
+	if version != 0 && version != current {
+		return 0, fmt.Errorf("%w: version %d is not current", ErrPreconditionFailed, version)
+	}
+	return current, nil
+}
+
+// staleError reports a versioned write that matched no row, the entity having
+// changed since it was read, as a failed precondition.
+func staleError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: changed by another request", ErrPreconditionFailed)
+	}
+	return err
+}
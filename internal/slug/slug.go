@@ -0,0 +1,78 @@
+// Package slug turns names into the lowercase ASCII words joined by hyphens
+// used in public URLs.
+package slug
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength bounds the length of a slug, suffix included.
+const MaxLength = 80
+
+var valid = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// letters spells out Latin letters that do not decompose into an ASCII
+// letter and combining marks.
+var letters = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "ae", "œ", "oe", "Œ", "oe", "ø", "o", "Ø", "o",
+	"ł", "l", "Ł", "l", "đ", "d", "Đ", "d", "ð", "d", "Ð", "d", "þ", "th", "Þ", "th",
+	"ı", "i", "&", " and ",
+)
+
+// Make derives a slug from s, transliterating accented Latin letters and
+// dropping everything else that is not a letter or digit. It returns "" when
+// nothing is left.
+func Make(s string) string {
+	s = letters.Replace(s)
+	if t, _, err := transform.String(transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn))), s); err == nil {
+		s = t
+	}
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	return truncate(b.String(), MaxLength)
+}
+
+// WithSuffix returns base numbered n, as used to tell apart slugs made from
+// equal names, shortening base if needed to stay within MaxLength.
+func WithSuffix(base string, n int) string {
+	suffix := "-" + strconv.Itoa(n)
+	return truncate(base, MaxLength-len(suffix)) + suffix
+}
+
+// Valid reports whether s is a well-formed slug. Slugs made only of digits
+// are not, as they would read as IDs.
+func Valid(s string) bool {
+	return len(s) <= MaxLength && valid.MatchString(s) && strings.Trim(s, "0123456789") != ""
+}
+
+// truncate cuts s to at most n bytes, at a hyphen when there is one.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, "-")
+}
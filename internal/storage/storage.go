@@ -0,0 +1,25 @@
+// Package storage keeps uploaded blobs such as media files. Blobs are
+// addressed by slash-separated keys chosen by the caller; the backend decides
+// where the bytes actually live.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotExist is returned when no blob is stored under a key.
+var ErrNotExist = errors.New("blob does not exist")
+
+// Storage is the interface implemented by blob backends.
+type Storage interface {
+	// Put stores the contents of r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns a reader for the blob stored under key. The caller must
+	// close it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,148 @@
+// Package xlsx writes single-sheet Office Open XML spreadsheets row by row,
+// without holding the sheet in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the files Writer produces.
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Writer streams rows into the single sheet of a workbook. Close must be
+// called to complete the file.
+type Writer struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+	err   error
+}
+
+// The parts of the package other than the sheet itself.
+var parts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// NewWriter starts a workbook on w whose only sheet is called sheetName.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	for _, p := range parts {
+		if err := writePart(zw, p.name, p.content); err != nil {
+			return nil, err
+		}
+	}
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writePart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	xw := &Writer{zw: zw, sheet: bufio.NewWriter(sheet)}
+	xw.sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return xw, nil
+}
+
+func writePart(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// WriteRow appends a row. Cells may be strings, bools, integers, float64 or
+// nil for an empty cell.
+func (w *Writer) WriteRow(cells ...any) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.rows++
+	row := strconv.Itoa(w.rows)
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		if cell == nil {
+			continue
+		}
+		ref := columnName(i) + row
+		switch v := cell.(type) {
+		case string:
+			w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w.sheet, []byte(v)); err != nil {
+				w.err = err
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			w.sheet.WriteString(`<c r="` + ref + `" t="b"><v>` + b + `</v></c>`)
+		case int:
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + strconv.Itoa(v) + `</v></c>`)
+		case int64:
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatInt(v, 10) + `</v></c>`)
+		case float64:
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatFloat(v, 'g', -1, 64) + `</v></c>`)
+		default:
+			w.err = fmt.Errorf("xlsx: unsupported cell type %T", cell)
+			return w.err
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
+
+// Close finishes the sheet and the file. It does not close the underlying
+// writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// columnName returns the letters naming the zero-based column i: A to Z,
+// then AA onwards.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,158 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+type sheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readPart returns the content of a part of a workbook.
+func readPart(t *testing.T, file []byte, name string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return data
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Catalog & <more>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]any{
+		{"type", "name", "price"},
+		{"service", `Tiles <"grout"> & sealing`, int64(4500), true, nil, 2, 1.5, "  padded  "},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file := buf.Bytes()
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels"} {
+		readPart(t, file, name)
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(readPart(t, file, "xl/workbook.xml"), &workbook); err != nil {
+		t.Fatalf("parse workbook.xml: %v", err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != "Catalog & <more>" {
+		t.Errorf("sheets %+v, want one named %q", workbook.Sheets, "Catalog & <more>")
+	}
+
+	var s sheet
+	if err := xml.Unmarshal(readPart(t, file, "xl/worksheets/sheet1.xml"), &s); err != nil {
+		t.Fatalf("parse sheet1.xml: %v", err)
+	}
+	if len(s.Rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(s.Rows))
+	}
+
+	type cell struct{ ref, typ, value string }
+	want := [][]cell{
+		{{"A1", "inlineStr", "type"}, {"B1", "inlineStr", "name"}, {"C1", "inlineStr", "price"}},
+		{
+			{"A2", "inlineStr", "service"},
+			{"B2", "inlineStr", `Tiles <"grout"> & sealing`},
+			{"C2", "", "4500"},
+			{"D2", "b", "1"},
+			// E2 is nil and left out.
+			{"F2", "", "2"},
+			{"G2", "", "1.5"},
+			{"H2", "inlineStr", "  padded  "},
+		},
+	}
+	for i, row := range s.Rows {
+		if wantRef := string(rune('1' + i)); row.R != wantRef {
+			t.Errorf("row %d numbered %q, want %q", i, row.R, wantRef)
+		}
+		var got []cell
+		for _, c := range row.Cells {
+			value := c.V
+			if c.T == "inlineStr" {
+				value = c.Inline
+			}
+			got = append(got, cell{c.R, c.T, value})
+		}
+		if len(got) != len(want[i]) {
+			t.Errorf("row %d: got cells %v, want %v", i+1, got, want[i])
+			continue
+		}
+		for j := range got {
+			if got[j] != want[i][j] {
+				t.Errorf("row %d: got cell %v, want %v", i+1, got[j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestWriterUnsupportedCell(t *testing.T) {
+	w, err := NewWriter(io.Discard, "Sheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow(struct{}{}); err == nil {
+		t.Fatal("WriteRow accepted a struct")
+	}
+	if err := w.WriteRow("a"); err == nil {
+		t.Error("WriteRow succeeded after a failed row")
+	}
+	if err := w.Close(); err == nil {
+		t.Error("Close succeeded after a failed row")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		i    int
+		want string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := columnName(tt.i); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.i, got, tt.want)
+		}
+	}
+}
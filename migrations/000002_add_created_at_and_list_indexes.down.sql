@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_services_category_name;
+DROP INDEX IF EXISTS idx_services_created;
+DROP INDEX IF EXISTS idx_services_name;
+DROP INDEX IF EXISTS idx_categories_created;
+
+ALTER TABLE services DROP COLUMN IF EXISTS created_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS created_at;
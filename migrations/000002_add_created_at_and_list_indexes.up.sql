@@ -0,0 +1,9 @@
+-- Track creation time so lists can be ordered by created order
+ALTER TABLE categories ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE services ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Indexes backing keyset pagination and list filters
+CREATE INDEX idx_categories_created ON categories(created_at, category_id);
+CREATE INDEX idx_services_name ON services(name, service_id);
+CREATE INDEX idx_services_created ON services(created_at, service_id);
+CREATE INDEX idx_services_category_name ON services(category_id, name, service_id);
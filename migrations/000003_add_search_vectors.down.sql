@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_services_search;
+DROP INDEX IF EXISTS idx_categories_search;
+
+ALTER TABLE services DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE categories DROP COLUMN IF EXISTS search_vector;
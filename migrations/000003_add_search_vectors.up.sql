@@ -0,0 +1,15 @@
+-- Full-text search vectors: names weigh more than descriptions
+ALTER TABLE categories ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+ALTER TABLE services ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_categories_search ON categories USING GIN (search_vector);
+CREATE INDEX idx_services_search ON services USING GIN (search_vector);
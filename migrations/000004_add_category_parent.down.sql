@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_categories_no_cycle ON categories;
+DROP FUNCTION IF EXISTS reject_category_cycle();
+
+DROP INDEX IF EXISTS idx_categories_parent;
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_parent_not_self;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,37 @@
+-- Allow categories to be nested under a parent category
+ALTER TABLE categories ADD COLUMN parent_id BIGINT REFERENCES categories(category_id);
+ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> category_id);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+-- A category may not move under one of its own descendants. Moves wait on
+-- each other so that each one checks the tree as the moves before it left
+-- it; two checked side by side, A under B and B under A, would otherwise
+-- both pass and leave a loop.
+CREATE FUNCTION reject_category_cycle()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext('category_tree'));
+    IF EXISTS (
+        WITH RECURSIVE subtree AS (
+            SELECT NEW.category_id AS category_id
+            UNION
+            SELECT ch.category_id
+            FROM categories ch
+            JOIN subtree st ON ch.parent_id = st.category_id
+        )
+        SELECT 1 FROM subtree WHERE category_id = NEW.parent_id
+    ) THEN
+        RAISE EXCEPTION 'category % cannot move under its own descendant %', NEW.category_id, NEW.parent_id;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_categories_no_cycle
+BEFORE UPDATE OF parent_id ON categories
+FOR EACH ROW
+WHEN (NEW.parent_id IS NOT NULL AND NEW.parent_id IS DISTINCT FROM OLD.parent_id)
+EXECUTE FUNCTION reject_category_cycle();
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS service_prices;
+
+ALTER TABLE services
+    DROP CONSTRAINT IF EXISTS services_price_bounds,
+    DROP COLUMN IF EXISTS max_price,
+    DROP COLUMN IF EXISTS min_price,
+    DROP COLUMN IF EXISTS pricing_unit,
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price;
@@ -0,0 +1,28 @@
+-- Current price of each service, in minor units of its currency
+ALTER TABLE services
+    ADD COLUMN price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN pricing_unit VARCHAR(32) NOT NULL DEFAULT 'flat'
+        CHECK (pricing_unit IN ('flat', 'per_hour', 'per_square_metre', 'per_item')),
+    ADD COLUMN min_price BIGINT CHECK (min_price >= 0),
+    ADD COLUMN max_price BIGINT CHECK (max_price >= 0),
+    ADD CONSTRAINT services_price_bounds CHECK (min_price IS NULL OR max_price IS NULL OR min_price <= max_price),
+    ADD CONSTRAINT services_price_in_bounds CHECK (price >= COALESCE(min_price, price) AND price <= COALESCE(max_price, price));
+
+-- Every price a service has had, with the moment it took effect
+CREATE TABLE service_prices (
+    price_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    price BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    pricing_unit VARCHAR(32) NOT NULL,
+    min_price BIGINT,
+    max_price BIGINT,
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_service_prices_service ON service_prices(service_id, effective_from DESC);
+
+-- Seed history so existing services have a starting price
+INSERT INTO service_prices (service_id, price, currency, pricing_unit, effective_from)
+SELECT service_id, price, currency, pricing_unit, created_at FROM services;
@@ -0,0 +1,3 @@
+-- Drop tables in reverse order
+DROP TABLE IF EXISTS provider_services;
+DROP TABLE IF EXISTS providers;
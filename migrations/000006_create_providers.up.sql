@@ -0,0 +1,22 @@
+-- Create providers table
+CREATE TABLE providers (
+    provider_id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Services each provider performs, with an optional provider-specific price
+CREATE TABLE provider_services (
+    provider_id BIGINT NOT NULL REFERENCES providers(provider_id) ON DELETE CASCADE,
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    price_override BIGINT CHECK (price_override >= 0),
+    PRIMARY KEY (provider_id, service_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_providers_name ON providers(name, provider_id);
+CREATE INDEX idx_providers_created ON providers(created_at, provider_id);
+CREATE INDEX idx_provider_services_service ON provider_services(service_id);
@@ -0,0 +1,3 @@
+-- Drop tables in reverse order
+DROP TABLE IF EXISTS booking_events;
+DROP TABLE IF EXISTS bookings;
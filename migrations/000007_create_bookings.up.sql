@@ -0,0 +1,36 @@
+-- Create bookings table
+CREATE TABLE bookings (
+    booking_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT NOT NULL REFERENCES services(service_id),
+    provider_id BIGINT REFERENCES providers(provider_id) ON DELETE SET NULL,
+    customer_name VARCHAR(255) NOT NULL,
+    customer_email VARCHAR(255) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'confirmed', 'in_progress', 'completed', 'cancelled')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmed_at TIMESTAMPTZ,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    CHECK (ends_at > starts_at)
+);
+
+-- Every status change a booking went through
+CREATE TABLE booking_events (
+    event_id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES bookings(booking_id) ON DELETE CASCADE,
+    from_status VARCHAR(32),
+    to_status VARCHAR(32) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_bookings_service ON bookings(service_id, starts_at);
+CREATE INDEX idx_bookings_provider ON bookings(provider_id, starts_at);
+CREATE INDEX idx_bookings_created ON bookings(created_at, booking_id);
+CREATE INDEX idx_booking_events_booking ON booking_events(booking_id, created_at);
@@ -0,0 +1,7 @@
+-- Drop tables in reverse order
+DROP TABLE IF EXISTS provider_blackouts;
+DROP TABLE IF EXISTS provider_exceptions;
+DROP TABLE IF EXISTS provider_working_hours;
+
+ALTER TABLE services DROP COLUMN IF EXISTS duration_minutes;
+ALTER TABLE providers DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,43 @@
+-- Providers keep their working hours in their own time zone
+ALTER TABLE providers ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- How long one booking of a service takes
+ALTER TABLE services ADD COLUMN duration_minutes INTEGER NOT NULL DEFAULT 60 CHECK (duration_minutes > 0);
+
+-- Regular weekly working windows (weekday 0 = Sunday, minutes after local midnight)
+CREATE TABLE provider_working_hours (
+    hours_id BIGSERIAL PRIMARY KEY,
+    provider_id BIGINT NOT NULL REFERENCES providers(provider_id) ON DELETE CASCADE,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute SMALLINT NOT NULL,
+    end_minute SMALLINT NOT NULL,
+    CHECK (start_minute >= 0 AND start_minute < end_minute AND end_minute <= 1440)
+);
+
+-- Date-specific hours replacing the weekly ones; a row without times is a day off
+CREATE TABLE provider_exceptions (
+    exception_id BIGSERIAL PRIMARY KEY,
+    provider_id BIGINT NOT NULL REFERENCES providers(provider_id) ON DELETE CASCADE,
+    exception_date DATE NOT NULL,
+    start_minute SMALLINT,
+    end_minute SMALLINT,
+    CHECK (
+        (start_minute IS NULL AND end_minute IS NULL) OR
+        (start_minute >= 0 AND start_minute < end_minute AND end_minute <= 1440)
+    )
+);
+
+-- Date ranges on which a provider is unavailable
+CREATE TABLE provider_blackouts (
+    blackout_id BIGSERIAL PRIMARY KEY,
+    provider_id BIGINT NOT NULL REFERENCES providers(provider_id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    CHECK (end_date >= start_date)
+);
+
+-- Create indexes
+CREATE INDEX idx_provider_working_hours_provider ON provider_working_hours(provider_id, weekday);
+CREATE INDEX idx_provider_exceptions_provider ON provider_exceptions(provider_id, exception_date);
+CREATE INDEX idx_provider_blackouts_provider ON provider_blackouts(provider_id, start_date);
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS haversine_km(DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION, DOUBLE PRECISION);
+DROP TABLE IF EXISTS service_areas;
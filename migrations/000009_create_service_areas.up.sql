@@ -0,0 +1,40 @@
+-- Where a service (or every service of a provider) is offered: either a list
+-- of postal codes or a circle around a centre point
+CREATE TABLE service_areas (
+    area_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT REFERENCES services(service_id) ON DELETE CASCADE,
+    provider_id BIGINT REFERENCES providers(provider_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('postal_codes', 'radius')),
+    postal_codes TEXT[] NOT NULL DEFAULT '{}',
+    center_lat DOUBLE PRECISION,
+    center_lng DOUBLE PRECISION,
+    radius_km DOUBLE PRECISION,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((service_id IS NULL) <> (provider_id IS NULL)),
+    CHECK (kind <> 'postal_codes' OR cardinality(postal_codes) > 0),
+    CHECK (kind <> 'radius' OR (
+        center_lat BETWEEN -90 AND 90 AND
+        center_lng BETWEEN -180 AND 180 AND
+        radius_km > 0
+    ))
+);
+
+-- Create indexes
+CREATE INDEX idx_service_areas_service ON service_areas(service_id);
+CREATE INDEX idx_service_areas_provider ON service_areas(provider_id);
+CREATE INDEX idx_service_areas_postal_codes ON service_areas USING GIN (postal_codes);
+
+-- Great-circle distance in kilometres between two lat/lng points. Rounding
+-- can take the argument of asin just past 1 for antipodal points, so it is
+-- capped there.
+CREATE FUNCTION haversine_km(lat1 DOUBLE PRECISION, lng1 DOUBLE PRECISION,
+                             lat2 DOUBLE PRECISION, lng2 DOUBLE PRECISION)
+RETURNS DOUBLE PRECISION
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$
+    SELECT 2 * 6371.0088 * asin(LEAST(1, sqrt(
+        power(sin(radians(lat2 - lat1) / 2), 2) +
+        cos(radians(lat1)) * cos(radians(lat2)) * power(sin(radians(lng2 - lng1) / 2), 2)
+    )))
+$$;
@@ -0,0 +1,4 @@
+-- Drop tables in reverse order
+DROP TABLE IF EXISTS review_votes;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS service_ratings;
@@ -0,0 +1,40 @@
+-- Running totals of approved reviews, maintained as reviews change. They
+-- are kept apart from services, as they change with every moderated review
+-- rather than with edits to the service; a service without a row has none.
+CREATE TABLE service_ratings (
+    service_id BIGINT PRIMARY KEY REFERENCES services(service_id) ON DELETE CASCADE,
+    review_count INTEGER NOT NULL DEFAULT 0 CHECK (review_count >= 0),
+    rating_total BIGINT NOT NULL DEFAULT 0 CHECK (rating_total >= 0)
+);
+
+-- Create reviews table: one review per customer per service. Only the
+-- SHA-256 of the token handed to the author for editing is kept.
+CREATE TABLE reviews (
+    review_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    customer_email VARCHAR(255) NOT NULL,
+    customer_name VARCHAR(255) NOT NULL DEFAULT '',
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    moderation_note TEXT NOT NULL DEFAULT '',
+    helpful_count INTEGER NOT NULL DEFAULT 0,
+    edit_token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (service_id, customer_email)
+);
+
+-- One helpful vote per voter per review
+CREATE TABLE review_votes (
+    review_id BIGINT NOT NULL REFERENCES reviews(review_id) ON DELETE CASCADE,
+    voter_email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, voter_email)
+);
+
+-- Create indexes
+CREATE INDEX idx_reviews_recent ON reviews(service_id, status, created_at, review_id);
+CREATE INDEX idx_reviews_helpful ON reviews(service_id, status, helpful_count, created_at, review_id);
+CREATE INDEX idx_reviews_status ON reviews(status, created_at, review_id);
@@ -0,0 +1,25 @@
+-- Create media table: images attached to either a service or a category
+CREATE TABLE media (
+    media_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT REFERENCES services(service_id) ON DELETE CASCADE,
+    category_id BIGINT REFERENCES categories(category_id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    width INTEGER NOT NULL CHECK (width > 0),
+    height INTEGER NOT NULL CHECK (height > 0),
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((service_id IS NULL) <> (category_id IS NULL))
+);
+
+-- Create indexes
+CREATE INDEX idx_media_service ON media(service_id, position, media_id) WHERE service_id IS NOT NULL;
+CREATE INDEX idx_media_category ON media(category_id, position, media_id) WHERE category_id IS NOT NULL;
+
+-- At most one primary image per owner
+CREATE UNIQUE INDEX idx_media_service_primary ON media(service_id) WHERE service_id IS NOT NULL AND is_primary;
+CREATE UNIQUE INDEX idx_media_category_primary ON media(category_id) WHERE category_id IS NOT NULL AND is_primary;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_services_delete_translations ON services;
+DROP TRIGGER IF EXISTS trg_categories_delete_translations ON categories;
+DROP FUNCTION IF EXISTS delete_translations();
+DROP TABLE IF EXISTS translations;
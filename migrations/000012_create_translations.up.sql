@@ -0,0 +1,37 @@
+-- Translated content for categories and services, one row per entity,
+-- locale and field. The default locale lives on the entities themselves.
+CREATE TABLE translations (
+    entity_type VARCHAR(16) NOT NULL CHECK (entity_type IN ('category', 'service')),
+    entity_id BIGINT NOT NULL,
+    locale VARCHAR(35) NOT NULL,
+    field VARCHAR(32) NOT NULL CHECK (field IN ('name', 'description')),
+    value TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, entity_id, locale, field)
+);
+
+-- Create indexes
+CREATE INDEX idx_translations_locale ON translations(entity_type, locale, field);
+
+-- Translations cannot reference their entity with a foreign key, so they are
+-- removed by triggers instead. The trigger arguments are the entity type and
+-- the name of the entity's key column.
+CREATE FUNCTION delete_translations()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM translations
+    WHERE entity_type = TG_ARGV[0]
+      AND entity_id = CAST(to_jsonb(OLD) ->> TG_ARGV[1] AS BIGINT);
+    RETURN OLD;
+END
+$$;
+
+CREATE TRIGGER trg_categories_delete_translations
+AFTER DELETE ON categories
+FOR EACH ROW EXECUTE FUNCTION delete_translations('category', 'category_id');
+
+CREATE TRIGGER trg_services_delete_translations
+AFTER DELETE ON services
+FOR EACH ROW EXECUTE FUNCTION delete_translations('service', 'service_id');
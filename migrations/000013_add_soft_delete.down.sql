@@ -0,0 +1,14 @@
+-- Trashed rows cannot be represented without the column, so they are purged.
+-- This fails if bookings still reference a trashed service.
+DELETE FROM services WHERE deleted_at IS NOT NULL;
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_services_deleted_at;
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_services_name_live;
+DROP INDEX IF EXISTS idx_categories_name_live;
+ALTER TABLE services ADD CONSTRAINT services_name_key UNIQUE (name);
+ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+
+ALTER TABLE services DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,14 @@
+-- Deleted categories and services stay in the trash until purged
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE services ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Names only need to be unique among live rows, so a trashed item does not
+-- block reusing its name
+ALTER TABLE categories DROP CONSTRAINT categories_name_key;
+ALTER TABLE services DROP CONSTRAINT services_name_key;
+CREATE UNIQUE INDEX idx_categories_name_live ON categories(name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_services_name_live ON services(name) WHERE deleted_at IS NULL;
+
+-- Create indexes
+CREATE INDEX idx_categories_deleted_at ON categories(deleted_at, category_id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_services_deleted_at ON services(deleted_at, service_id) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,38 @@
+-- Append-only record of catalog mutations
+CREATE TABLE audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    -- Whether the change was made with the admin token; otherwise actor is
+    -- only what the client claimed
+    admin BOOLEAN NOT NULL DEFAULT FALSE,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore')),
+    entity_type VARCHAR(16) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at, audit_id);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at, audit_id);
+
+-- Entries can never be changed or removed once written
+CREATE FUNCTION reject_audit_log_change()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END
+$$;
+
+CREATE TRIGGER trg_audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+
+CREATE TRIGGER trg_audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION reject_audit_log_change();
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS trg_media_touch_owner ON media;
+DROP TRIGGER IF EXISTS trg_translations_touch_entity ON translations;
+DROP FUNCTION IF EXISTS touch_media_owner();
+DROP FUNCTION IF EXISTS touch_translated_entity();
+DROP FUNCTION IF EXISTS touch_entity(TEXT, BIGINT);
+
+DROP TRIGGER IF EXISTS trg_services_bump_version ON services;
+DROP TRIGGER IF EXISTS trg_categories_bump_version ON categories;
+DROP FUNCTION IF EXISTS bump_version();
+
+ALTER TABLE services DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
@@ -0,0 +1,83 @@
+-- Every change to a category or service moves it to a new version, which
+-- clients echo back in If-Match to detect concurrent edits
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE services ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE FUNCTION bump_version()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW IS DISTINCT FROM OLD THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER trg_categories_bump_version
+BEFORE UPDATE ON categories
+FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+CREATE TRIGGER trg_services_bump_version
+BEFORE UPDATE ON services
+FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+-- Translations and media are part of what a client sees of an entity, so
+-- changing them moves the entity to a new version too. The row's key columns
+-- never change, so NEW and OLD name the same entity.
+CREATE FUNCTION touch_entity(target_type TEXT, target_id BIGINT)
+RETURNS VOID
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF target_type = 'category' THEN
+        UPDATE categories SET version = version + 1 WHERE category_id = target_id;
+    ELSE
+        UPDATE services SET version = version + 1 WHERE service_id = target_id;
+    END IF;
+END
+$$;
+
+CREATE FUNCTION touch_translated_entity()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM touch_entity(OLD.entity_type, OLD.entity_id);
+    ELSE
+        PERFORM touch_entity(NEW.entity_type, NEW.entity_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE FUNCTION touch_media_owner()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    m media%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        m := OLD;
+    ELSE
+        m := NEW;
+    END IF;
+    IF m.service_id IS NOT NULL THEN
+        PERFORM touch_entity('service', m.service_id);
+    ELSE
+        PERFORM touch_entity('category', m.category_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER trg_translations_touch_entity
+AFTER INSERT OR UPDATE OR DELETE ON translations
+FOR EACH ROW EXECUTE FUNCTION touch_translated_entity();
+
+CREATE TRIGGER trg_media_touch_owner
+AFTER INSERT OR UPDATE OR DELETE ON media
+FOR EACH ROW EXECUTE FUNCTION touch_media_owner();
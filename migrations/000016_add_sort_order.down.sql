@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_services_category_sort_order;
+DROP INDEX IF EXISTS idx_services_sort_order;
+DROP INDEX IF EXISTS idx_categories_parent_sort_order;
+DROP INDEX IF EXISTS idx_categories_sort_order;
+
+ALTER TABLE services DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE categories DROP COLUMN IF EXISTS sort_order;
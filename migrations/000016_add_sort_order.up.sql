@@ -0,0 +1,10 @@
+-- Manual display position set by merchandising; lists order by it first and
+-- by name among equal positions. Categories are positioned among their
+-- siblings, services within their category.
+ALTER TABLE categories ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_categories_sort_order ON categories(sort_order, name, category_id);
+CREATE INDEX idx_categories_parent_sort_order ON categories(parent_id, sort_order, name);
+CREATE INDEX idx_services_sort_order ON services(sort_order, name, service_id);
+CREATE INDEX idx_services_category_sort_order ON services(category_id, sort_order, name, service_id);
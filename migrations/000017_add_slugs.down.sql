@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS trg_services_track_slug ON services;
+DROP TRIGGER IF EXISTS trg_categories_track_slug ON categories;
+DROP FUNCTION IF EXISTS track_slug();
+
+DROP TABLE IF EXISTS slug_redirects;
+
+DROP INDEX IF EXISTS idx_services_slug;
+DROP INDEX IF EXISTS idx_categories_slug;
+ALTER TABLE services DROP COLUMN IF EXISTS slug;
+ALTER TABLE categories DROP COLUMN IF EXISTS slug;
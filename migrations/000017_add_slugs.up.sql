@@ -0,0 +1,99 @@
+-- URL-friendly unique names. The application transliterates names into
+-- slugs; rows created before that get a plain ASCII slug here, with
+-- duplicates told apart by their ID. Names with no ASCII letters or digits
+-- fall back to the entity type and ID.
+ALTER TABLE categories ADD COLUMN slug TEXT;
+ALTER TABLE services ADD COLUMN slug TEXT;
+
+WITH base AS (
+    SELECT category_id,
+           TRIM(BOTH '-' FROM LEFT(regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'), 60)) AS slug
+    FROM categories
+), fixed AS (
+    SELECT category_id,
+           CASE WHEN slug = '' THEN 'category-' || category_id
+                WHEN slug ~ '^[0-9-]*$' THEN 'category-' || slug
+                ELSE slug END AS slug
+    FROM base
+), numbered AS (
+    SELECT category_id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY category_id) AS n
+    FROM fixed
+)
+UPDATE categories c
+SET slug = CASE WHEN n.n = 1 THEN n.slug ELSE n.slug || '-' || c.category_id END
+FROM numbered n
+WHERE c.category_id = n.category_id;
+
+WITH base AS (
+    SELECT service_id,
+           TRIM(BOTH '-' FROM LEFT(regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'), 60)) AS slug
+    FROM services
+), fixed AS (
+    SELECT service_id,
+           CASE WHEN slug = '' THEN 'service-' || service_id
+                WHEN slug ~ '^[0-9-]*$' THEN 'service-' || slug
+                ELSE slug END AS slug
+    FROM base
+), numbered AS (
+    SELECT service_id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY service_id) AS n
+    FROM fixed
+)
+UPDATE services s
+SET slug = CASE WHEN n.n = 1 THEN n.slug ELSE n.slug || '-' || s.service_id END
+FROM numbered n
+WHERE s.service_id = n.service_id;
+
+-- Slugs stay reserved while an entity is in the trash so restoring it never
+-- clashes
+ALTER TABLE categories ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE services ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug);
+CREATE UNIQUE INDEX idx_services_slug ON services(slug);
+
+-- Slugs an entity has given up, so links using them can be sent on to its
+-- current slug
+CREATE TABLE slug_redirects (
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('category', 'service')),
+    slug TEXT NOT NULL,
+    entity_id BIGINT NOT NULL,
+    retired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, slug)
+);
+
+CREATE INDEX idx_slug_redirects_entity ON slug_redirects(entity_type, entity_id);
+
+-- Keeps slug_redirects in step with the slugs in use. Arguments are the
+-- entity type and the name of the table's ID column.
+CREATE FUNCTION track_slug()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    entity TEXT := TG_ARGV[0];
+    id_column TEXT := TG_ARGV[1];
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM slug_redirects
+        WHERE entity_type = entity AND entity_id = CAST(to_jsonb(OLD) ->> id_column AS BIGINT);
+        RETURN NULL;
+    END IF;
+
+    -- A slug taken again no longer redirects, whoever had it before
+    DELETE FROM slug_redirects WHERE entity_type = entity AND slug = NEW.slug;
+    IF TG_OP = 'UPDATE' AND NEW.slug <> OLD.slug THEN
+        INSERT INTO slug_redirects (entity_type, slug, entity_id)
+        VALUES (entity, OLD.slug, CAST(to_jsonb(NEW) ->> id_column AS BIGINT))
+        ON CONFLICT (entity_type, slug)
+        DO UPDATE SET entity_id = EXCLUDED.entity_id, retired_at = NOW();
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER trg_categories_track_slug
+AFTER INSERT OR UPDATE OF slug OR DELETE ON categories
+FOR EACH ROW EXECUTE FUNCTION track_slug('category', 'category_id');
+
+CREATE TRIGGER trg_services_track_slug
+AFTER INSERT OR UPDATE OF slug OR DELETE ON services
+FOR EACH ROW EXECUTE FUNCTION track_slug('service', 'service_id');
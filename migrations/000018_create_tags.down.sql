@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS service_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,17 @@
+-- Labels finer-grained than categories ("eco-friendly", "same-day"). Filters
+-- refer to tags by slug.
+CREATE TABLE tags (
+    tag_id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE service_tags (
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(tag_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (service_id, tag_id)
+);
+
+CREATE INDEX idx_service_tags_tag ON service_tags(tag_id, service_id);
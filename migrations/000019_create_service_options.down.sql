@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS options;
+DROP TABLE IF EXISTS option_groups;
+
+DROP FUNCTION IF EXISTS touch_option_service();
+DROP FUNCTION IF EXISTS touch_option_group_service();
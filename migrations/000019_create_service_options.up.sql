@@ -0,0 +1,77 @@
+-- Configurable parts of a service ("number of rooms", "inside fridge"). A
+-- group is answered by picking one option (single), any number of options
+-- (multi) or a count of each option (quantity); min_select and max_select
+-- bound the options picked or, for quantity groups, the total count.
+CREATE TABLE option_groups (
+    group_id BIGSERIAL PRIMARY KEY,
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('single', 'multi', 'quantity')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER CHECK (max_select >= 1),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (max_select IS NULL OR min_select <= max_select)
+);
+
+-- An option changes the price by a fixed amount, in minor units of the
+-- service currency, or by a percentage of the base price in basis points.
+-- Negative modifiers are discounts.
+CREATE TABLE options (
+    option_id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES option_groups(group_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    modifier_type VARCHAR(16) NOT NULL DEFAULT 'fixed' CHECK (modifier_type IN ('fixed', 'percent')),
+    modifier_amount BIGINT NOT NULL DEFAULT 0,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_option_groups_service ON option_groups(service_id, sort_order, group_id);
+CREATE INDEX idx_options_group ON options(group_id, sort_order, option_id);
+
+-- Option groups and options are served as part of their service, so changing
+-- them moves the service to a new version.
+CREATE FUNCTION touch_option_group_service()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM touch_entity('service', OLD.service_id);
+    ELSE
+        PERFORM touch_entity('service', NEW.service_id);
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE FUNCTION touch_option_service()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    o options%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        o := OLD;
+    ELSE
+        o := NEW;
+    END IF;
+    -- Options deleted along with their group find no group; the group's own
+    -- trigger touches the service then.
+    PERFORM touch_entity('service', g.service_id)
+    FROM option_groups g
+    WHERE g.group_id = o.group_id;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER trg_option_groups_touch_service
+AFTER INSERT OR UPDATE OR DELETE ON option_groups
+FOR EACH ROW EXECUTE FUNCTION touch_option_group_service();
+
+CREATE TRIGGER trg_options_touch_service
+AFTER INSERT OR UPDATE OR DELETE ON options
+FOR EACH ROW EXECUTE FUNCTION touch_option_service();
@@ -0,0 +1,27 @@
+-- Packages of services sold together ("Move-in package"). The price is
+-- either fixed or a discount, in basis points, off the sum of the member
+-- prices. A bundle switched off because a member service was deactivated or
+-- deleted says why in inactive_reason.
+CREATE TABLE bundles (
+    bundle_id BIGSERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES categories(category_id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    inactive_reason TEXT NOT NULL DEFAULT '',
+    price_rule VARCHAR(16) NOT NULL CHECK (price_rule IN ('fixed', 'discount')),
+    price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    discount_bp INTEGER NOT NULL DEFAULT 0 CHECK (discount_bp BETWEEN 0 AND 10000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE bundle_items (
+    bundle_id BIGINT NOT NULL REFERENCES bundles(bundle_id) ON DELETE CASCADE,
+    service_id BIGINT NOT NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (bundle_id, service_id)
+);
+
+CREATE INDEX idx_bundles_category ON bundles(category_id, name);
+CREATE INDEX idx_bundle_items_service ON bundle_items(service_id);
@@ -0,0 +1,17 @@
+DROP FUNCTION IF EXISTS live_active(BOOLEAN, TIMESTAMPTZ, TIMESTAMPTZ);
+
+ALTER TABLE services
+    DROP CONSTRAINT IF EXISTS services_publish_window,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS categories_publish_window,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at;
+
+-- Entries already recorded cannot be removed, so the old check only applies
+-- to new ones.
+ALTER TABLE audit_log
+    DROP CONSTRAINT audit_log_action_check,
+    ADD CONSTRAINT audit_log_action_check
+        CHECK (action IN ('create', 'update', 'delete', 'restore')) NOT VALID;
@@ -0,0 +1,38 @@
+-- Scheduled transitions of categories and services: publish_at switches an
+-- item on and unpublish_at switches it off. The scheduler applies a
+-- transition once it comes due and clears its column; either order is
+-- allowed, so a seasonal service can be switched off now and on again later.
+ALTER TABLE categories
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD COLUMN unpublish_at TIMESTAMPTZ,
+    ADD CONSTRAINT categories_publish_window CHECK (publish_at <> unpublish_at);
+ALTER TABLE services
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD COLUMN unpublish_at TIMESTAMPTZ,
+    ADD CONSTRAINT services_publish_window CHECK (publish_at <> unpublish_at);
+
+-- The scheduler records the transitions it applies
+ALTER TABLE audit_log
+    DROP CONSTRAINT audit_log_action_check,
+    ADD CONSTRAINT audit_log_action_check
+        CHECK (action IN ('create', 'update', 'delete', 'restore', 'publish', 'unpublish'));
+
+CREATE INDEX idx_categories_publish_at ON categories(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_categories_unpublish_at ON categories(unpublish_at) WHERE unpublish_at IS NOT NULL;
+CREATE INDEX idx_services_publish_at ON services(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX idx_services_unpublish_at ON services(unpublish_at) WHERE unpublish_at IS NOT NULL;
+
+-- Whether an item is active right now: its flag, unless a transition has
+-- come due that the scheduler has not applied yet, in which case the later
+-- of the due transitions decides. Reads use this so that windows hold to the
+-- second whatever the scheduler interval.
+CREATE FUNCTION live_active(active BOOLEAN, publish TIMESTAMPTZ, unpublish TIMESTAMPTZ)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE PARALLEL SAFE
+AS $$
+    SELECT CASE
+        WHEN publish <= NOW() AND (unpublish IS NULL OR unpublish > NOW() OR unpublish < publish) THEN TRUE
+        WHEN unpublish <= NOW() THEN FALSE
+        ELSE active
+    END
+$$;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_services_delete_revisions ON services;
+DROP TRIGGER IF EXISTS trg_categories_delete_revisions ON categories;
+DROP FUNCTION IF EXISTS delete_revisions();
+DROP TABLE IF EXISTS revisions;
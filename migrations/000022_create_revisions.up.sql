@@ -0,0 +1,48 @@
+-- Saved versions of the editable content of categories and services. Edits
+-- are kept as an entity's draft, at most one at a time, until published;
+-- the published revision is the one the entity row holds, and every earlier
+-- one is archived. Content has the writable fields in their JSON form.
+-- base_content is the content of the entity a draft was made from, so that
+-- publishing it can tell the draft's edits from changes made live since.
+CREATE TABLE revisions (
+    revision_id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(16) NOT NULL CHECK (entity_type IN ('category', 'service')),
+    entity_id BIGINT NOT NULL,
+    number INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('draft', 'published', 'archived')),
+    content JSONB NOT NULL,
+    restored_from INTEGER,
+    base_content JSONB CHECK (status <> 'draft' OR base_content IS NOT NULL),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_by VARCHAR(255),
+    published_at TIMESTAMPTZ,
+    UNIQUE (entity_type, entity_id, number)
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_revisions_draft ON revisions(entity_type, entity_id) WHERE status = 'draft';
+CREATE UNIQUE INDEX idx_revisions_published ON revisions(entity_type, entity_id) WHERE status = 'published';
+
+-- Like translations, revisions are removed along with their entity by
+-- triggers. The arguments are the entity type and its key column.
+CREATE FUNCTION delete_revisions()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    DELETE FROM revisions
+    WHERE entity_type = TG_ARGV[0]
+      AND entity_id = CAST(to_jsonb(OLD) ->> TG_ARGV[1] AS BIGINT);
+    RETURN OLD;
+END
+$$;
+
+CREATE TRIGGER trg_categories_delete_revisions
+AFTER DELETE ON categories
+FOR EACH ROW EXECUTE FUNCTION delete_revisions('category', 'category_id');
+
+CREATE TRIGGER trg_services_delete_revisions
+AFTER DELETE ON services
+FOR EACH ROW EXECUTE FUNCTION delete_revisions('service', 'service_id');